@@ -0,0 +1,70 @@
+package autoenv
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	encodingRaw       = "raw"
+	encodingBase64    = "base64"
+	encodingBase64URL = "base64url"
+	encodingHex       = "hex"
+)
+
+// isBytesType reports whether t is a []byte or [N]byte, which are decoded
+// as a whole according to the field encoding instead of element by element.
+// Byte arrays without an encoding are split like any other array.
+func isBytesType(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() == reflect.Uint8
+}
+
+// decodeBytes sets a []byte or [N]byte field from val decoded with encoding,
+// which defaults to raw. Arrays must receive exactly N bytes.
+func decodeBytes(fv reflect.Value, val, encoding string) error {
+	b, err := decodeBinary(val, encoding)
+	if err != nil {
+		return err
+	}
+
+	if fv.Kind() == reflect.Array {
+		if len(b) != fv.Len() {
+			return fmt.Errorf("decoded %d bytes, want %d", len(b), fv.Len())
+		}
+		reflect.Copy(fv, reflect.ValueOf(b))
+		return nil
+	}
+
+	fv.Set(reflect.ValueOf(b).Convert(fv.Type()))
+	return nil
+}
+
+func decodeBinary(val, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "", encodingRaw:
+		return []byte(val), nil
+	case encodingBase64:
+		b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(val, "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 value: %w", err)
+		}
+		return b, nil
+	case encodingBase64URL:
+		b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(val, "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64url value: %w", err)
+		}
+		return b, nil
+	case encodingHex:
+		b, err := hex.DecodeString(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex value: %w", err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
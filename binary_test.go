@@ -0,0 +1,86 @@
+package autoenv
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeBinary(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		encoding string
+		want     []byte
+		wantErr  bool
+	}{
+		{name: "raw by default", value: "s3cr3t", want: []byte("s3cr3t")},
+		{name: "raw", value: "a,b", encoding: "raw", want: []byte("a,b")},
+		{name: "base64 padded", value: "aGVsbG8=", encoding: "base64", want: []byte("hello")},
+		{name: "base64 unpadded", value: "aGVsbG8", encoding: "base64", want: []byte("hello")},
+		{name: "base64url", value: "-_8", encoding: "base64url", want: []byte{0xfb, 0xff}},
+		{name: "hex", value: "deadBEEF", encoding: "hex", want: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{name: "encoding is case-insensitive", value: "00ff", encoding: "HEX", want: []byte{0x00, 0xff}},
+		{name: "invalid base64", value: "!!!", encoding: "base64", wantErr: true},
+		{name: "invalid hex", value: "xyz", encoding: "hex", wantErr: true},
+		{name: "unknown encoding", value: "x", encoding: "rot13", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBinary(tt.value, tt.encoding)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("decodeBinary() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeBinary() = unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("decodeBinary() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBinaryFields(t *testing.T) {
+	t.Setenv("BIN_SIGNING_KEY", "c2lnbmluZy1rZXk=")
+	t.Setenv("BIN_SALT", "0001020304050607")
+	t.Setenv("BIN_TOKEN", "plain,text")
+	t.Setenv("BIN_KEYS", "YQ==;Yg==")
+
+	type Config struct {
+		SigningKey []byte   `encoding:"base64"`
+		Salt       [8]byte  `env:"SALT,encoding=hex"`
+		Token      []byte   `json:"token"`
+		Keys       [][]byte `env:"KEYS,sep=;,encoding=base64"`
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("BIN")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		SigningKey: []byte("signing-key"),
+		Salt:       [8]byte{0, 1, 2, 3, 4, 5, 6, 7},
+		Token:      []byte("plain,text"),
+		Keys:       [][]byte{[]byte("a"), []byte("b")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadBinaryArrayLength(t *testing.T) {
+	t.Setenv("BINLEN_SALT", "0001")
+
+	var cfg struct {
+		Salt [8]byte `encoding:"hex"`
+	}
+	if err := NewLoader(WithPrefix("BINLEN")).Load(&cfg); !IsLoadError(err) {
+		t.Errorf("Load() = %v, want *LoadError", err)
+	}
+}
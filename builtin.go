@@ -0,0 +1,178 @@
+package autoenv
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	locationType    = reflect.TypeOf(time.Location{})
+	locationPtrType = reflect.TypeOf((*time.Location)(nil))
+
+	urlType       = reflect.TypeOf(url.URL{})
+	urlPtrType    = reflect.TypeOf((*url.URL)(nil))
+	ipType        = reflect.TypeOf(net.IP{})
+	ipNetType     = reflect.TypeOf(net.IPNet{})
+	ipNetPtrType  = reflect.TypeOf((*net.IPNet)(nil))
+	addrType      = reflect.TypeOf(netip.Addr{})
+	prefixType    = reflect.TypeOf(netip.Prefix{})
+	addrPortType  = reflect.TypeOf(netip.AddrPort{})
+	regexpType    = reflect.TypeOf(regexp.Regexp{})
+	regexpPtrType = reflect.TypeOf((*regexp.Regexp)(nil))
+)
+
+const (
+	layoutUnix      = "unix"
+	layoutUnixMilli = "unixmilli"
+	layoutUnixMicro = "unixmicro"
+	layoutUnixNano  = "unixnano"
+)
+
+// namedLayouts maps the names of the time package layouts, usable in the
+// layout tag or option, to their values.
+var namedLayouts = map[string]string{
+	"Layout":      time.Layout,
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// isBuiltinType reports whether t is a struct type the loader decodes from a
+// single variable without relying on a decoding interface.
+func isBuiltinType(t reflect.Type) bool {
+	switch t {
+	case timeType, locationType, urlType, ipNetType, addrType, prefixType, addrPortType, regexpType:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeBuiltin decodes the standard library types that need more than
+// their decoding interfaces, such as time.Time with custom layouts, or that
+// deserve errors naming what was expected, such as URLs and IP addresses.
+func decodeBuiltin(fv reflect.Value, val string, spec fieldSpec) (bool, error) {
+	switch fv.Type() {
+	case timeType:
+		t, err := parseTime(val, spec.layout)
+		if err != nil {
+			return true, err
+		}
+		fv.Set(reflect.ValueOf(t))
+	case locationPtrType, locationType:
+		loc, err := time.LoadLocation(val)
+		if err != nil {
+			return true, err
+		}
+		setPointerOrValue(fv, reflect.ValueOf(loc))
+	case urlType, urlPtrType:
+		u, err := url.Parse(val)
+		if err != nil {
+			return true, fmt.Errorf("invalid URL %q: %w", val, err)
+		}
+		setPointerOrValue(fv, reflect.ValueOf(u))
+	case ipType:
+		ip := net.ParseIP(val)
+		if ip == nil {
+			return true, fmt.Errorf("invalid IP address %q", val)
+		}
+		fv.Set(reflect.ValueOf(ip))
+	case ipNetType, ipNetPtrType:
+		_, ipNet, err := net.ParseCIDR(val)
+		if err != nil {
+			return true, fmt.Errorf("invalid CIDR %q: %w", val, err)
+		}
+		setPointerOrValue(fv, reflect.ValueOf(ipNet))
+	case addrType:
+		addr, err := netip.ParseAddr(val)
+		if err != nil {
+			return true, fmt.Errorf("invalid IP address %q: %w", val, err)
+		}
+		fv.Set(reflect.ValueOf(addr))
+	case prefixType:
+		prefix, err := netip.ParsePrefix(val)
+		if err != nil {
+			return true, fmt.Errorf("invalid IP prefix %q: %w", val, err)
+		}
+		fv.Set(reflect.ValueOf(prefix))
+	case addrPortType:
+		addrPort, err := netip.ParseAddrPort(val)
+		if err != nil {
+			return true, fmt.Errorf("invalid IP address and port %q: %w", val, err)
+		}
+		fv.Set(reflect.ValueOf(addrPort))
+	case regexpType, regexpPtrType:
+		re, err := regexp.Compile(val)
+		if err != nil {
+			return true, fmt.Errorf("invalid regular expression %q: %w", val, err)
+		}
+		setPointerOrValue(fv, reflect.ValueOf(re))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// setPointerOrValue sets fv to ptr, or to the value ptr points to when fv
+// is not a pointer.
+func setPointerOrValue(fv, ptr reflect.Value) {
+	if fv.Kind() == reflect.Ptr {
+		fv.Set(ptr)
+		return
+	}
+	fv.Set(ptr.Elem())
+}
+
+// parseTime parses val with layout, which is either a time package layout
+// name such as DateOnly, one of the unix, unixmilli, unixmicro or unixnano
+// timestamp formats, or a literal layout. The default layout is RFC3339.
+func parseTime(val, layout string) (time.Time, error) {
+	switch strings.ToLower(layout) {
+	case layoutUnix, layoutUnixMilli, layoutUnixMicro, layoutUnixNano:
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s timestamp %q: %w", layout, val, err)
+		}
+		switch strings.ToLower(layout) {
+		case layoutUnixMilli:
+			return time.UnixMilli(n).UTC(), nil
+		case layoutUnixMicro:
+			return time.UnixMicro(n).UTC(), nil
+		case layoutUnixNano:
+			return time.Unix(0, n).UTC(), nil
+		default:
+			return time.Unix(n, 0).UTC(), nil
+		}
+	}
+
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if named, ok := namedLayouts[layout]; ok {
+		layout = named
+	}
+	return time.Parse(layout, val)
+}
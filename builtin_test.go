@@ -0,0 +1,226 @@
+package autoenv
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		layout  string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "default RFC3339",
+			value: "2024-01-02T15:04:05Z",
+			want:  time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name:   "named DateOnly",
+			value:  "2024-01-02",
+			layout: "DateOnly",
+			want:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "named RFC1123",
+			value:  "Tue, 02 Jan 2024 15:04:05 UTC",
+			layout: "RFC1123",
+			want:   time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name:   "literal layout",
+			value:  "02/01/2024",
+			layout: "02/01/2006",
+			want:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TimeOnly",
+			value:  "03:30:00",
+			layout: "TimeOnly",
+			want:   time.Date(0, 1, 1, 3, 30, 0, 0, time.UTC),
+		},
+		{
+			name:   "unix seconds",
+			value:  "1704207845",
+			layout: "unix",
+			want:   time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		{
+			name:   "unix millis",
+			value:  "1704207845123",
+			layout: "unixmilli",
+			want:   time.Date(2024, 1, 2, 15, 4, 5, 123e6, time.UTC),
+		},
+		{
+			name:    "invalid unix",
+			value:   "yesterday",
+			layout:  "unix",
+			wantErr: true,
+		},
+		{
+			name:    "layout mismatch",
+			value:   "2024-01-02",
+			layout:  "RFC3339",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTime(tt.value, tt.layout)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseTime() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTime() = unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTimeFields(t *testing.T) {
+	t.Setenv("TIMES_RELEASE", "2024-01-02")
+	t.Setenv("TIMES_WINDOW_START", "03:30:00")
+	t.Setenv("TIMES_EXPIRES", "1704207845")
+	t.Setenv("TIMES_HOLIDAYS", "2024-12-25;2024-12-26")
+	t.Setenv("TIMES_ZONE", "America/New_York")
+	t.Setenv("TIMES_FALLBACK_ZONE", "UTC")
+
+	type Config struct {
+		Release     time.Time      `layout:"DateOnly"`
+		WindowStart time.Time      `env:"WINDOW_START,layout=TimeOnly"`
+		Expires     *time.Time     `env:"EXPIRES,layout=unix"`
+		Holidays    []time.Time    `env:"HOLIDAYS,sep=;,layout=DateOnly"`
+		Zone        *time.Location `json:"zone"`
+		Fallback    time.Location  `env:"FALLBACK_ZONE"`
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("TIMES")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !got.Release.Equal(want) {
+		t.Errorf("Release = %v, want %v", got.Release, want)
+	}
+	if got.WindowStart.Hour() != 3 || got.WindowStart.Minute() != 30 {
+		t.Errorf("WindowStart = %v, want 03:30", got.WindowStart)
+	}
+	if got.Expires == nil || got.Expires.Unix() != 1704207845 {
+		t.Errorf("Expires = %v, want unix 1704207845", got.Expires)
+	}
+	if len(got.Holidays) != 2 || got.Holidays[1].Day() != 26 {
+		t.Errorf("Holidays = %v, want two dates", got.Holidays)
+	}
+	if got.Zone == nil || got.Zone.String() != "America/New_York" {
+		t.Errorf("Zone = %v, want America/New_York", got.Zone)
+	}
+	if got.Fallback.String() != "UTC" {
+		t.Errorf("Fallback = %v, want UTC", got.Fallback.String())
+	}
+}
+
+func TestIsBuiltinType(t *testing.T) {
+	for _, typ := range []reflect.Type{timeType, locationType} {
+		if !isBuiltinType(typ) {
+			t.Errorf("isBuiltinType(%s) = false, want true", typ)
+		}
+	}
+	if isBuiltinType(reflect.TypeOf(struct{}{})) {
+		t.Errorf("isBuiltinType(struct{}) = true, want false")
+	}
+}
+
+func TestLoadNetworkFields(t *testing.T) {
+	t.Setenv("NET_ENDPOINT", "https://api.example.com:8443/v1?debug=1")
+	t.Setenv("NET_CALLBACK", "http://localhost/cb")
+	t.Setenv("NET_BIND_IP", "10.0.0.1")
+	t.Setenv("NET_ADDR", "::1")
+	t.Setenv("NET_ALLOWED", "10.0.0.0/8, 192.168.0.0/16")
+	t.Setenv("NET_LISTEN", "127.0.0.1:8080")
+	t.Setenv("NET_TRUSTED", "172.16.0.0/12")
+	t.Setenv("NET_PATTERN", "^user-[0-9]+$")
+
+	type Config struct {
+		Endpoint url.URL
+		Callback *url.URL
+		BindIP   net.IP `env:"BIND_IP"`
+		Addr     netip.Addr
+		Allowed  []netip.Prefix
+		Listen   netip.AddrPort
+		Trusted  *net.IPNet
+		Pattern  *regexp.Regexp
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("NET")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	if got.Endpoint.Host != "api.example.com:8443" || got.Endpoint.Query().Get("debug") != "1" {
+		t.Errorf("Endpoint = %v", got.Endpoint.String())
+	}
+	if got.Callback == nil || got.Callback.Path != "/cb" {
+		t.Errorf("Callback = %v", got.Callback)
+	}
+	if !got.BindIP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("BindIP = %v", got.BindIP)
+	}
+	if got.Addr != netip.IPv6Loopback() {
+		t.Errorf("Addr = %v", got.Addr)
+	}
+	wantAllowed := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.0.0/16")}
+	if !reflect.DeepEqual(got.Allowed, wantAllowed) {
+		t.Errorf("Allowed = %v, want %v", got.Allowed, wantAllowed)
+	}
+	if got.Listen != netip.MustParseAddrPort("127.0.0.1:8080") {
+		t.Errorf("Listen = %v", got.Listen)
+	}
+	if got.Trusted == nil || got.Trusted.String() != "172.16.0.0/12" {
+		t.Errorf("Trusted = %v", got.Trusted)
+	}
+	if got.Pattern == nil || !got.Pattern.MatchString("user-42") {
+		t.Errorf("Pattern = %v", got.Pattern)
+	}
+}
+
+func TestLoadNetworkFieldErrors(t *testing.T) {
+	t.Setenv("NETERR_ENDPOINT", "http://[::1")
+	t.Setenv("NETERR_BIND_IP", "10.0.0.300")
+	t.Setenv("NETERR_TRUSTED", "10.0.0.0/40")
+	t.Setenv("NETERR_PATTERN", "(unclosed")
+
+	var cfg struct {
+		Endpoint *url.URL
+		BindIP   net.IP `env:"BIND_IP"`
+		Trusted  *net.IPNet
+		Pattern  *regexp.Regexp
+	}
+	err := NewLoader(WithPrefix("NETERR")).Load(&cfg)
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || len(loadErr.Fields) != 4 {
+		t.Fatalf("Load() = %v, want four field failures", err)
+	}
+	for i, want := range []string{"invalid URL", "invalid IP address", "invalid CIDR", "invalid regular expression"} {
+		if !strings.Contains(loadErr.Fields[i].Error(), want) {
+			t.Errorf("Fields[%d] = %q, want it to contain %q", i, loadErr.Fields[i].Error(), want)
+		}
+	}
+}
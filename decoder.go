@@ -0,0 +1,118 @@
+package autoenv
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+// EnvDecoder can be implemented by any type, on either a value or a pointer
+// receiver, to take full control of how it is decoded from a variable.
+type EnvDecoder interface {
+	DecodeEnv(value string) error
+}
+
+// parserFunc converts a raw value into a reflect.Value assignable to the
+// type it was registered for with WithParser.
+type parserFunc func(val string) (reflect.Value, error)
+
+// decodeWithParser runs the parser registered for the exact type of fv, if
+// any, or else the one registered for a pointer to it, dereferencing the result.
+func (l *Loader) decodeWithParser(fv reflect.Value, val string) (bool, error) {
+	if parse, ok := l.options.parsers[fv.Type()]; ok {
+		v, err := parse(val)
+		if err != nil {
+			return true, err
+		}
+		fv.Set(v)
+		return true, nil
+	}
+
+	parse, ok := l.options.parsers[reflect.PointerTo(fv.Type())]
+	if !ok {
+		return false, nil
+	}
+
+	v, err := parse(val)
+	if err != nil {
+		return true, err
+	}
+	if v.IsNil() {
+		fv.SetZero()
+	} else {
+		fv.Set(v.Elem())
+	}
+	return true, nil
+}
+
+// setter matches flag.Value and similar types exposing Set(string) error.
+type setter interface {
+	Set(value string) error
+}
+
+// decodeWithInterface decodes val through EnvDecoder, encoding.TextUnmarshaler,
+// encoding.BinaryUnmarshaler or setter, in that order. It reports false when
+// the type implements none of them.
+func decodeWithInterface(fv reflect.Value, val string) (bool, error) {
+	if fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		return false, nil
+	}
+	if !implementsDecoder(reflect.PointerTo(fv.Type())) {
+		return false, nil
+	}
+
+	target := fv
+	if !fv.CanAddr() {
+		target = reflect.New(fv.Type()).Elem()
+		target.Set(fv)
+	}
+
+	if err := decodeInto(target.Addr().Interface(), val); err != nil {
+		return true, err
+	}
+
+	if target != fv {
+		fv.Set(target)
+	}
+	return true, nil
+}
+
+func implementsDecoder(t reflect.Type) bool {
+	return t.Implements(envDecoderType) ||
+		t.Implements(textUnmarshalerType) ||
+		t.Implements(binaryUnmarshalerType) ||
+		t.Implements(setterType)
+}
+
+func decodeInto(v any, val string) error {
+	switch d := v.(type) {
+	case EnvDecoder:
+		return d.DecodeEnv(val)
+	case encoding.TextUnmarshaler:
+		return d.UnmarshalText([]byte(val))
+	case encoding.BinaryUnmarshaler:
+		return d.UnmarshalBinary([]byte(val))
+	case setter:
+		return d.Set(val)
+	default:
+		return nil
+	}
+}
+
+var (
+	envDecoderType        = reflect.TypeOf((*EnvDecoder)(nil)).Elem()
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	setterType            = reflect.TypeOf((*setter)(nil)).Elem()
+)
+
+// decodeJSON decodes val into fv with encoding/json, for fields using the
+// json option. Any type is accepted, including structs, maps and interfaces.
+func decodeJSON(fv reflect.Value, val string) error {
+	target := reflect.New(fv.Type())
+	if err := json.Unmarshal([]byte(val), target.Interface()); err != nil {
+		return err
+	}
+	fv.Set(target.Elem())
+	return nil
+}
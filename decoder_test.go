@@ -0,0 +1,315 @@
+package autoenv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+type testRegion struct {
+	Code string
+}
+
+func (r *testRegion) DecodeEnv(value string) error {
+	r.Code = strings.ToUpper(value)
+	return nil
+}
+
+type testBinary struct {
+	raw string
+}
+
+func (b *testBinary) UnmarshalBinary(data []byte) error {
+	b.raw = string(data)
+	return nil
+}
+
+type testLabels map[string]bool
+
+func (l testLabels) Set(value string) error {
+	for _, s := range strings.Split(value, "|") {
+		l[s] = true
+	}
+	return nil
+}
+
+func (l testLabels) String() string {
+	return fmt.Sprint(map[string]bool(l))
+}
+
+func TestSetFieldValueDecoders(t *testing.T) {
+	loader := NewLoader()
+
+	tests := []struct {
+		name    string
+		field   reflect.Value
+		value   string
+		want    any
+		wantErr bool
+	}{
+		{
+			name:  "TextUnmarshaler",
+			field: reflect.New(reflect.TypeOf(testLevel(0))).Elem(),
+			value: "error",
+			want:  testLevel(2),
+		},
+		{
+			name:    "TextUnmarshaler error",
+			field:   reflect.New(reflect.TypeOf(testLevel(0))).Elem(),
+			value:   "verbose",
+			wantErr: true,
+		},
+		{
+			name:  "EnvDecoder on struct",
+			field: reflect.New(reflect.TypeOf(testRegion{})).Elem(),
+			value: "eu-west-1",
+			want:  testRegion{Code: "EU-WEST-1"},
+		},
+		{
+			name:  "EnvDecoder through pointer",
+			field: reflect.New(reflect.TypeOf(&testRegion{})).Elem(),
+			value: "us-east-1",
+			want:  &testRegion{Code: "US-EAST-1"},
+		},
+		{
+			name:  "BinaryUnmarshaler",
+			field: reflect.New(reflect.TypeOf(testBinary{})).Elem(),
+			value: "raw",
+			want:  testBinary{raw: "raw"},
+		},
+		{
+			name:  "Setter with value receiver",
+			field: reflect.ValueOf(&testLabels{}).Elem(),
+			value: "a|b",
+			want:  testLabels{"a": true, "b": true},
+		},
+		{
+			name:  "slice of TextUnmarshaler",
+			field: reflect.New(reflect.TypeOf([]testLevel{})).Elem(),
+			value: "debug, info",
+			want:  []testLevel{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loader.setFieldValue(tt.field, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("setFieldValue() = got no error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setFieldValue() = unexpected error: %v", err)
+			}
+			if got := tt.field.Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setFieldValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDecoderFields(t *testing.T) {
+	now := time.Now().Truncate(time.Second).UTC()
+	t.Setenv("DEC_LEVEL", "info")
+	t.Setenv("DEC_REGION", "ap-south-1")
+	t.Setenv("DEC_STARTED", now.Format(time.RFC3339))
+	t.Setenv("DEC_BAD_LEVEL", "loud")
+
+	var cfg struct {
+		Level    testLevel
+		Region   testRegion
+		Started  time.Time
+		BadLevel testLevel
+	}
+	err := NewLoader(WithPrefix("DEC")).Load(&cfg)
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || len(loadErr.Fields) != 1 || loadErr.Fields[0].Field != "BadLevel" {
+		t.Fatalf("Load() = %v, want a single BadLevel failure", err)
+	}
+	if cfg.Level != 1 || cfg.Region.Code != "AP-SOUTH-1" || !cfg.Started.Equal(now) {
+		t.Errorf("Load() = %+v", cfg)
+	}
+}
+
+type testUUID [4]byte
+
+type testDecimal struct {
+	units int64
+	scale int
+}
+
+func parseTestUUID(s string) (testUUID, error) {
+	var id testUUID
+	if len(s) != len(id) {
+		return id, fmt.Errorf("invalid uuid %q", s)
+	}
+	copy(id[:], s)
+	return id, nil
+}
+
+func parseTestDecimal(s string) (testDecimal, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	var d testDecimal
+	_, err := fmt.Sscan(whole+frac, &d.units)
+	d.scale = len(frac)
+	return d, err
+}
+
+func TestWithParser(t *testing.T) {
+	t.Setenv("PARSER_ID", "abcd")
+	t.Setenv("PARSER_OPTIONAL_ID", "efgh")
+	t.Setenv("PARSER_IDS", "aaaa, bbbb")
+	t.Setenv("PARSER_PRICE", "12.50")
+	t.Setenv("PARSER_LEVEL", "loud")
+
+	type Config struct {
+		ID         testUUID
+		OptionalID *testUUID
+		Ids        []testUUID
+		Price      testDecimal
+		Level      testLevel
+	}
+
+	loader := NewLoader(
+		WithPrefix("PARSER"),
+		WithParser(parseTestUUID),
+		WithParser(parseTestDecimal),
+		WithParser(func(s string) (testLevel, error) {
+			return testLevel(len(s)), nil
+		}),
+	)
+
+	var got Config
+	if err := loader.Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		ID:         testUUID{'a', 'b', 'c', 'd'},
+		OptionalID: &testUUID{'e', 'f', 'g', 'h'},
+		Ids:        []testUUID{{'a', 'a', 'a', 'a'}, {'b', 'b', 'b', 'b'}},
+		Price:      testDecimal{units: 1250, scale: 2},
+		Level:      testLevel(4),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithPointerParser(t *testing.T) {
+	t.Setenv("PTR_PARSER_PRICE", "12.50")
+	t.Setenv("PTR_PARSER_OPTIONAL_PRICE", "3.1")
+
+	type Config struct {
+		Price         testDecimal
+		OptionalPrice *testDecimal
+	}
+
+	loader := NewLoader(
+		WithPrefix("PTR_PARSER"),
+		WithParser(func(s string) (*testDecimal, error) {
+			d, err := parseTestDecimal(s)
+			return &d, err
+		}),
+	)
+
+	var got Config
+	if err := loader.Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		Price:         testDecimal{units: 1250, scale: 2},
+		OptionalPrice: &testDecimal{units: 31, scale: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWithParserError(t *testing.T) {
+	t.Setenv("PARSER_ERR_ID", "too-long")
+
+	var cfg struct {
+		ID testUUID
+	}
+	err := NewLoader(WithPrefix("PARSER_ERR"), WithParser(parseTestUUID)).Load(&cfg)
+	if !IsLoadError(err) || !strings.Contains(err.Error(), `invalid uuid "too-long"`) {
+		t.Errorf("Load() = %v, want LoadError with parser failure", err)
+	}
+}
+
+func TestLoadJSONFields(t *testing.T) {
+	t.Setenv("JSON_RATE_LIMITS", `{"default": 100, "burst": 20}`)
+	t.Setenv("JSON_UPSTREAM", `{"host": "a.local", "port": 8080}`)
+	t.Setenv("JSON_FEATURES", `["a", "b"]`)
+	t.Setenv("JSON_EXTRA", `{"nested": [1, true]}`)
+	t.Setenv("JSON_OPTIONAL", `{"host": "b.local"}`)
+
+	type Upstream struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	type Config struct {
+		RateLimits map[string]int `env:"RATE_LIMITS,json"`
+		Upstream   Upstream       `env:"UPSTREAM,json"`
+		Features   []string       `env:"FEATURES,json"`
+		Extra      any            `env:"EXTRA,json"`
+		Optional   *Upstream      `env:"OPTIONAL,json"`
+		Fallback   Upstream       `env:"FALLBACK,json,default={\"port\":80}"`
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("JSON")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		RateLimits: map[string]int{"default": 100, "burst": 20},
+		Upstream:   Upstream{Host: "a.local", Port: 8080},
+		Features:   []string{"a", "b"},
+		Extra:      map[string]any{"nested": []any{float64(1), true}},
+		Optional:   &Upstream{Host: "b.local"},
+		Fallback:   Upstream{Port: 80},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadJSONFieldError(t *testing.T) {
+	t.Setenv("JSONERR_LIMITS", `{"default": "many"}`)
+
+	var cfg struct {
+		Limits map[string]int `env:"LIMITS,json"`
+	}
+	err := NewLoader(WithPrefix("JSONERR")).Load(&cfg)
+
+	var typeErr *json.UnmarshalTypeError
+	if !IsLoadError(err) || !errors.As(err, &typeErr) {
+		t.Errorf("Load() = %v, want *LoadError wrapping *json.UnmarshalTypeError", err)
+	}
+}
@@ -0,0 +1,79 @@
+package autoenv
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func (l *Loader) loadDirs() map[string]string {
+	if len(l.options.dirs) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string)
+	for _, dir := range l.options.dirs {
+		if dir == "" {
+			continue
+		}
+
+		dirValues, err := l.loadDir(dir)
+		if err != nil {
+			l.options.logger.ErrorF("failed to load directory: %s", err)
+			continue
+		}
+		maps.Copy(values, dirValues)
+
+		if l.isVerbose() {
+			l.options.logger.DebugF("loaded directory: %s (%d keys)", dir, len(dirValues))
+		}
+	}
+	return values
+}
+
+// loadDir reads every regular file of dir, following symlinks, as a value
+// keyed by its normalized file name. Hidden entries, including the
+// Kubernetes ..data layout, and subdirectories are skipped. Entries that
+// cannot be read are logged and skipped so they do not hide the others.
+func (l *Loader) loadDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		// Kubernetes volumes swap their content through the ..data symlink and
+		// a timestamped directory, the keys themselves link through ..data.
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			l.options.logger.ErrorF("failed to load directory entry: %s", err)
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		val, err := readValueFile(path)
+		if err != nil {
+			l.options.logger.ErrorF("failed to load directory entry: %s", err)
+			continue
+		}
+		values[l.dirKey(name)] = val
+	}
+	return values, nil
+}
+
+// dirKey turns a file name into the key getEnvKey builds for a field of the
+// same name, so dbPassword, db-password and DB_PASSWORD files all provide
+// DB_PASSWORD. Files are named without the loader prefix, which is always added.
+func (l *Loader) dirKey(name string) string {
+	return l.getEnvKey(strings.ReplaceAll(name, "-", "_"))
+}
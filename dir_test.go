@@ -0,0 +1,178 @@
+package autoenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDirFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeDirFiles(t, dir, map[string]string{
+		"DB_PASSWORD": "s3cret\n",
+		"log-level":   "debug",
+		"cache.size":  "10MiB\r\n",
+		".hidden":     "skipped",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0o700); err != nil {
+		t.Fatalf("failed to create nested dir: %v", err)
+	}
+
+	got, err := NewLoader().loadDir(dir)
+	if err != nil {
+		t.Fatalf("loadDir() error = %v", err)
+	}
+
+	want := map[string]string{
+		"DB_PASSWORD": "s3cret",
+		"LOG_LEVEL":   "debug",
+		"CACHE_SIZE":  "10MiB",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("loadDir() = %v, want %v", got, want)
+	}
+	for key, val := range want {
+		if got[key] != val {
+			t.Errorf("loadDir()[%q] = %q, want %q", key, got[key], val)
+		}
+	}
+}
+
+func TestLoadDirKubernetesLayout(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..2026_10_18_12_00_00.123456789")
+	if err := os.Mkdir(data, 0o700); err != nil {
+		t.Fatalf("failed to create data dir: %v", err)
+	}
+	writeDirFiles(t, data, map[string]string{"username": "admin\n"})
+
+	links := map[string]string{
+		"..data":   filepath.Base(data),
+		"username": filepath.Join("..data", "username"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	got, err := NewLoader().loadDir(dir)
+	if err != nil {
+		t.Fatalf("loadDir() error = %v", err)
+	}
+	if len(got) != 1 || got["USERNAME"] != "admin" {
+		t.Errorf("loadDir() = %v, want map[USERNAME:admin]", got)
+	}
+}
+
+func TestLoadDirSkipsBrokenEntries(t *testing.T) {
+	dir := t.TempDir()
+	writeDirFiles(t, dir, map[string]string{"good": "value"})
+	if err := os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "dangling")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	logger := &recordingLogger{}
+	got, err := NewLoader(WithLogger(logger)).loadDir(dir)
+	if err != nil {
+		t.Fatalf("loadDir() error = %v", err)
+	}
+	if len(got) != 1 || got["GOOD"] != "value" {
+		t.Errorf("loadDir() = %v, want map[GOOD:value]", got)
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "dangling") {
+		t.Errorf("loadDir() logged %q, want the dangling entry", logger.lines)
+	}
+}
+
+func TestDirKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		file   string
+		want   string
+	}{
+		{name: "plain", prefix: "", file: "DB_PASSWORD", want: "DB_PASSWORD"},
+		{name: "dashes and dots", prefix: "", file: "db-pass.word", want: "DB_PASS_WORD"},
+		{name: "camel case", prefix: "", file: "dbPassword", want: "DB_PASSWORD"},
+		{name: "prefix added", prefix: "APP", file: "db-password", want: "APP_DB_PASSWORD"},
+		{name: "prefix always added", prefix: "app", file: "app-port", want: "APP_APP_PORT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLoader(WithPrefix(tt.prefix)).dirKey(tt.file); got != tt.want {
+				t.Errorf("dirKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithDir(t *testing.T) {
+	type Config struct {
+		Username string `json:"username"`
+		Port     int    `json:"port"`
+		Token    string `json:"token" required:"true"`
+	}
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    Config
+	}{
+		{
+			name:    "EnvOverDir",
+			options: nil,
+			want:    Config{Username: "admin", Port: 9090, Token: "from-dir"},
+		},
+		{
+			name:    "FileOverEnv",
+			options: []Option{WithPrecedence(FileOverEnv)},
+			want:    Config{Username: "admin", Port: 7070, Token: "from-dir"},
+		},
+		{
+			name:    "SourcesDirsFirst",
+			options: []Option{WithSources(SourceDirs, SourceEnv)},
+			want:    Config{Username: "admin", Port: 8080, Token: "from-dir"},
+		},
+		{
+			name:    "EmptyPathIgnored",
+			options: []Option{WithDir("")},
+			want:    Config{Username: "admin", Port: 9090, Token: "from-dir"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeDirFiles(t, dir, map[string]string{
+				"username": "admin\n",
+				"port":     "8080",
+				"token":    "from-dir",
+			})
+			envFile := filepath.Join(t.TempDir(), ".env")
+			writeDirFiles(t, filepath.Dir(envFile), map[string]string{".env": "APP_PORT=7070\n"})
+			t.Setenv("APP_PORT", "9090")
+
+			options := append([]Option{WithPrefix("APP"), WithPaths([]string{envFile}), WithDir(dir)}, tt.options...)
+			var cfg Config
+			if err := NewLoader(options...).Load(&cfg); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg != tt.want {
+				t.Errorf("Load() = %+v, want %+v", cfg, tt.want)
+			}
+		})
+	}
+}
@@ -31,11 +31,13 @@
 //
 //   - Automatic SNAKE_CASE conversion for field names
 //   - Support for nested structs
-//   - Optional .env file loading
+//   - Optional .env file loading (kept private to the loader unless WithExport is used)
+//   - Directory-of-files sources for Kubernetes volumes and Docker or systemd secrets
 //   - Environment variable prefixing
 //   - Field ignoring capabilities
 //   - Custom logging support
 //   - Slice support (comma-separated values)
+//   - Default values through the default tag
 //
 // Supported Types:
 //   - string
@@ -44,15 +46,24 @@
 //   - uint, uint8, uint16, uint32, uint64
 //   - float32, float64
 //   - []string, []bool, []int, []uint, []float64
+//   - time.Time, time.Duration, time.Location, ByteSize
+//   - url.URL, net.IP, net.IPNet, netip.Addr, netip.Prefix, netip.AddrPort, regexp.Regexp
+//   - types implementing EnvDecoder, encoding.TextUnmarshaler,
+//     encoding.BinaryUnmarshaler or Set(string) error
 //
 // Configuration Options:
 //
 //	autoenv.Load(cfg,
 //	    autoenv.WithPrefix("APP"),        // Add prefix to all env vars
 //	    autoenv.WithEnvFile(),            // Load from .env file
+//	    autoenv.WithExport(),             // Also export .env values into os.Environ
+//	    autoenv.WithDir("/run/secrets"),  // Load one value per file in the directory
+//	    autoenv.WithPrecedence(autoenv.FileOverEnv), // Let .env values override the environment
 //	    autoenv.WithVerbose(),            // Enable verbose logging
 //	    autoenv.WithOnlyEnvTag(),         // Only use env tags
 //	    autoenv.WithIgnore("debug"),      // Ignore specific fields
+//	    autoenv.WithParser(uuid.Parse),   // Custom conversion for a type
+//	    autoenv.WithFileSuffix("_FILE"),  // Read DB_PASSWORD from DB_PASSWORD_FILE
 //	)
 //
 // For more information and examples, visit: https://go.g3deon.com/autoenv
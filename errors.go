@@ -4,11 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 var (
 	ErrNilLoader = errors.New("global loader is nil")
 	ErrNilInput  = errors.New("input is nil")
+
+	ErrEmptyValue  = errors.New("value is empty")
+	ErrSparseIndex = errors.New("indexes are not contiguous from 0")
 )
 
 type errUnsupportedKind struct {
@@ -24,3 +29,120 @@ func IsUnsupportedKindError(err error) bool {
 	ok := errors.As(err, &errUnsupportedKind)
 	return ok
 }
+
+type MissingField struct {
+	Key   string
+	Field string
+}
+
+type MissingError struct {
+	Fields []MissingField
+}
+
+func (e *MissingError) Error() string {
+	missing := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		missing[i] = fmt.Sprintf("%s (%s)", f.Key, f.Field)
+	}
+	return fmt.Sprintf("missing required variables: %s", strings.Join(missing, ", "))
+}
+
+func IsMissingError(err error) bool {
+	var missingError *MissingError
+	ok := errors.As(err, &missingError)
+	return ok
+}
+
+type OverflowError struct {
+	Value string
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("value %q overflows %s", e.Value, e.Type)
+}
+
+func (e *OverflowError) Unwrap() error {
+	return e.Err
+}
+
+func IsOverflowError(err error) bool {
+	var overflowError *OverflowError
+	ok := errors.As(err, &overflowError)
+	return ok
+}
+
+// numberError turns out of range strconv errors into an *OverflowError for t.
+func numberError(t reflect.Type, err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) && errors.Is(numErr.Err, strconv.ErrRange) {
+		return &OverflowError{Value: numErr.Num, Type: t, Err: err}
+	}
+	return err
+}
+
+// secretError hides the message of errors from fields tagged as secret,
+// since conversion errors usually quote the offending value.
+type secretError struct {
+	err error
+}
+
+func (e *secretError) Error() string {
+	return "invalid value (redacted)"
+}
+
+func (e *secretError) Unwrap() error {
+	return e.err
+}
+
+type FieldError struct {
+	Field string
+	Key   string
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s, %s): %s", e.Field, e.Key, e.Type, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+type LoadError struct {
+	Fields  []*FieldError
+	Missing *MissingError
+}
+
+func (e *LoadError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to load %d field(s):", len(e.Fields))
+	for _, f := range e.Fields {
+		b.WriteString("\n  - ")
+		b.WriteString(f.Error())
+	}
+	if e.Missing != nil {
+		b.WriteString("\n")
+		b.WriteString(e.Missing.Error())
+	}
+	return b.String()
+}
+
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields)+1)
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	if e.Missing != nil {
+		errs = append(errs, e.Missing)
+	}
+	return errs
+}
+
+func IsLoadError(err error) bool {
+	var loadError *LoadError
+	ok := errors.As(err, &loadError)
+	return ok
+}
@@ -1,7 +1,9 @@
 package autoenv
 
 import (
+	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -10,12 +12,27 @@ import (
 const (
 	envTag             = "env"
 	jsonTag            = "json"
+	defaultTag         = "default"
+	requiredTag        = "required"
+	allowEmptyTag      = "allowEmpty"
+	envPrefixTag       = "envPrefix"
+	sepTag             = "sep"
+	layoutTag          = "layout"
+	encodingTag        = "encoding"
 	fieldPathSeparator = "."
+
+	mapKeyValueSeparator = ":"
 )
 
 type fieldInfo struct {
 	field reflect.StructField
 	name  string
+	path  string
+	spec  fieldSpec
+
+	// elemFields is set for slices of structs, which are loaded from
+	// indexed keys using these fields relative to each element.
+	elemFields []fieldInfo
 }
 
 func isFieldIgnored(target, parent, ignore string) bool {
@@ -66,23 +83,68 @@ func (l *Loader) getStructFields(structType reflect.Type, parent string) []field
 
 func (l *Loader) processStructField(field reflect.StructField, parent string) *[]fieldInfo {
 	fieldType := l.getFieldType(field.Type)
-	name := l.resolveFieldName(field)
+	tag := resolveFieldTag(field)
+	name := tag.name
 
 	isIgnored := l.isIgnoring(name, parent)
 	if isIgnored {
 		return nil
 	}
 
-	if fieldType.Kind() == reflect.Struct {
-		return l.processNestedStruct(field, fieldType, name, parent)
+	asJSON := tag.options.has(optJSON)
+	if fieldType.Kind() == reflect.Struct && !asJSON && !l.isDecodable(fieldType) {
+		return l.processNestedStruct(field, fieldType, tag, parent)
+	}
+
+	var elemFields []fieldInfo
+	if elemType, ok := l.structSliceElem(fieldType); ok && !asJSON {
+		elemFields = l.getStructFields(elemType, joinParent(parent, name))
+		if len(elemFields) == 0 {
+			return nil
+		}
 	}
 
 	return &[]fieldInfo{{
-		field: field,
-		name:  name,
+		field:      field,
+		name:       name,
+		path:       field.Name,
+		spec:       parseFieldSpec(field, tag),
+		elemFields: elemFields,
 	}}
 }
 
+// isDecodable reports whether a struct type is loaded from a single variable
+// rather than flattened into one variable per field.
+func (l *Loader) isDecodable(t reflect.Type) bool {
+	if _, ok := l.options.parsers[t]; ok {
+		return true
+	}
+	if _, ok := l.options.parsers[reflect.PointerTo(t)]; ok {
+		return true
+	}
+	return isBuiltinType(t) || implementsDecoder(reflect.PointerTo(t))
+}
+
+// structSliceElem returns the struct element type of a slice loaded from
+// indexed keys, skipping slices handled by a parser or a decoder.
+func (l *Loader) structSliceElem(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Slice {
+		return nil, false
+	}
+	if _, ok := l.options.parsers[t]; ok {
+		return nil, false
+	}
+	if implementsDecoder(reflect.PointerTo(t)) {
+		return nil, false
+	}
+
+	elem := l.getFieldType(t.Elem())
+	if elem.Kind() != reflect.Struct || l.isDecodable(elem) {
+		return nil, false
+	}
+	return elem, true
+}
+
 func (l *Loader) getFieldType(t reflect.Type) reflect.Type {
 	if t.Kind() == reflect.Ptr {
 		return t.Elem()
@@ -90,16 +152,34 @@ func (l *Loader) getFieldType(t reflect.Type) reflect.Type {
 	return t
 }
 
-func (l *Loader) processNestedStruct(field reflect.StructField, fieldType reflect.Type, name, parent string) *[]fieldInfo {
-	subFields := l.getStructFields(fieldType, joinParent(parent, name))
+// processNestedStruct flattens the fields of a nested struct. Their names are
+// prefixed with the field name followed by "_", or with the envPrefix tag as
+// is, while inline (or squash) structs contribute their names unchanged like
+// promoted Go fields.
+func (l *Loader) processNestedStruct(field reflect.StructField, fieldType reflect.Type, tag fieldTag, parent string) *[]fieldInfo {
+	inline := tag.options.has(optInline) || tag.options.has(optSquash)
+
+	prefix := tag.name + "_"
+	if v, ok := field.Tag.Lookup(envPrefixTag); ok {
+		prefix = v
+	}
+
+	subParent := joinParent(parent, tag.name)
+	if inline {
+		prefix = ""
+		subParent = parent
+	}
+
+	subFields := l.getStructFields(fieldType, subParent)
 	if len(subFields) == 0 {
 		return nil
 	}
 
 	result := make([]fieldInfo, 0, len(subFields))
 	for _, sf := range subFields {
-		sf.field.Index = append(field.Index, sf.field.Index...)
-		sf.name = name + "_" + sf.name
+		sf.field.Index = slices.Concat(field.Index, sf.field.Index)
+		sf.name = prefix + sf.name
+		sf.path = field.Name + fieldPathSeparator + sf.path
 		result = append(result, sf)
 	}
 	return &result
@@ -113,6 +193,13 @@ func (l *Loader) shouldIncludeField(f reflect.StructField) bool {
 		return false
 	}
 
+	if resolveFieldTag(f).skip {
+		if l.isVerbose() {
+			l.options.logger.DebugF("%s: excluding (%q tag)", f.Name, tagSkip)
+		}
+		return false
+	}
+
 	if l.isOnlyEnvTag() {
 		_, ok := f.Tag.Lookup(envTag)
 		if l.isVerbose() && !ok {
@@ -124,24 +211,178 @@ func (l *Loader) shouldIncludeField(f reflect.StructField) bool {
 	return true
 }
 
-func (l *Loader) resolveFieldName(f reflect.StructField) string {
-	if v, ok := f.Tag.Lookup(envTag); ok {
-		return v
+// fieldSpec is the loading behavior of a field, parsed once from its env
+// tag options and the standalone default, required and allowEmpty tags:
+//
+//	env:"NAME,required,notEmpty,allowEmpty,unset,file,expand,secret,json,sep=;,default=8080"
+//
+// Defaults containing commas must use the default tag.
+type fieldSpec struct {
+	defaultValue string
+	hasDefault   bool
+
+	required   bool
+	notEmpty   bool
+	allowEmpty bool
+	unset      bool
+	file       bool
+	expand     bool
+	secret     bool
+	json       bool
+
+	// seps are the separators of nested lists and maps from the outermost
+	// level inward, level is the nesting level being decoded. See separator.
+	seps  []string
+	kvSep string
+	level int
+
+	// base is the integer base, only meaningful when hasBase is set. Base 0
+	// accepts 0x, 0o and 0b prefixes and digit-separating underscores.
+	base    int
+	hasBase bool
+
+	// layout is the time.Time layout, see parseTime.
+	layout string
+	// encoding is the []byte and [N]byte encoding, see decodeBinary.
+	encoding string
+}
+
+const (
+	optRequired   = "required"
+	optNotEmpty   = "notEmpty"
+	optAllowEmpty = "allowEmpty"
+	optUnset      = "unset"
+	optFile       = "file"
+	optExpand     = "expand"
+	optSecret     = "secret"
+	optSep        = "sep"
+	optKVSep      = "kvsep"
+	optDefault    = "default"
+	optInline     = "inline"
+	optSquash     = "squash"
+	optBase       = "base"
+	optLayout     = "layout"
+	optEncoding   = "encoding"
+	optJSON       = "json"
+)
+
+func parseFieldSpec(f reflect.StructField, tag fieldTag) fieldSpec {
+	spec := fieldSpec{
+		required:   tag.options.has(optRequired) || isTagEnabled(f, requiredTag),
+		notEmpty:   tag.options.has(optNotEmpty),
+		allowEmpty: tag.options.has(optAllowEmpty) || isTagEnabled(f, allowEmptyTag),
+		unset:      tag.options.has(optUnset),
+		file:       tag.options.has(optFile),
+		expand:     tag.options.has(optExpand),
+		secret:     tag.options.has(optSecret),
+		json:       tag.options.has(optJSON),
+	}
+
+	spec.defaultValue, spec.hasDefault = f.Tag.Lookup(defaultTag)
+	if v, ok := tag.options.value(optDefault); ok && !spec.hasDefault {
+		spec.defaultValue, spec.hasDefault = v, true
+	}
+
+	if v, ok := f.Tag.Lookup(sepTag); ok && v != "" {
+		spec.seps = strings.Fields(v)
+	} else if v, ok := tag.options.value(optSep); ok && v != "" {
+		spec.seps = strings.Fields(v)
+	}
+	if v, ok := tag.options.value(optKVSep); ok && v != "" {
+		spec.kvSep = v
+	}
+
+	spec.layout = f.Tag.Get(layoutTag)
+	if v, ok := tag.options.value(optLayout); ok && spec.layout == "" {
+		spec.layout = v
+	}
+
+	spec.encoding = f.Tag.Get(encodingTag)
+	if v, ok := tag.options.value(optEncoding); ok && spec.encoding == "" {
+		spec.encoding = v
+	}
+
+	if v, ok := tag.options.value(optBase); ok {
+		if base, err := strconv.Atoi(v); err == nil && (base == 0 || (base >= 2 && base <= 36)) {
+			spec.base, spec.hasBase = base, true
+		}
+	}
+
+	return spec
+}
+
+func (s fieldSpec) keyValueSeparator() string {
+	if s.kvSep != "" {
+		return s.kvSep
+	}
+	return mapKeyValueSeparator
+}
+
+// next returns the spec used for the elements of the current list or map.
+func (s fieldSpec) next() fieldSpec {
+	s.level++
+	return s
+}
+
+func (s fieldSpec) numberBase() int {
+	if s.hasBase {
+		return s.base
 	}
+	return 10
+}
 
-	if v, ok := f.Tag.Lookup(jsonTag); ok {
-		return v
+func isTagEnabled(f reflect.StructField, tag string) bool {
+	v, ok := f.Tag.Lookup(tag)
+	if !ok {
+		return false
+	}
+	if v == "" {
+		return true
 	}
+	enabled, err := strconv.ParseBool(v)
+	return err == nil && enabled
+}
 
-	return f.Name
+func (l *Loader) resolveFieldName(f reflect.StructField) string {
+	return resolveFieldTag(f).name
+}
+
+// setEmptyValue handles variables explicitly set to an empty string: slices
+// become empty (non-nil) slices and everything else is reset to its zero value.
+func setEmptyValue(fv reflect.Value) {
+	if fv.Kind() == reflect.Slice {
+		fv.Set(reflect.MakeSlice(fv.Type(), 0, 0))
+		return
+	}
+	fv.Set(reflect.Zero(fv.Type()))
 }
 
 func (l *Loader) setFieldValue(fv reflect.Value, val string) error {
+	return l.setValue(fv, val, fieldSpec{})
+}
+
+func (l *Loader) setValue(fv reflect.Value, val string, spec fieldSpec) error {
+	if ok, err := l.decodeWithParser(fv, val); ok {
+		return err
+	}
+
+	if ok, err := decodeBuiltin(fv, val, spec); ok {
+		return err
+	}
+
 	if fv.Kind() == reflect.Ptr {
 		if fv.IsNil() {
 			fv.Set(reflect.New(fv.Type().Elem()))
 		}
-		return l.setFieldValue(fv.Elem(), val)
+		return l.setValue(fv.Elem(), val, spec)
+	}
+
+	if ok, err := decodeWithInterface(fv, val); ok {
+		return err
+	}
+
+	if isBytesType(fv.Type()) && (fv.Kind() == reflect.Slice || spec.encoding != "") {
+		return decodeBytes(fv, val, spec.encoding)
 	}
 
 	switch fv.Kind() {
@@ -155,51 +396,116 @@ func (l *Loader) setFieldValue(fv reflect.Value, val string) error {
 		fv.SetBool(b)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		if fv.Type() == reflect.TypeOf(time.Duration(0)) {
-			d, err := time.ParseDuration(val)
+			d, err := parseDuration(val)
 			if err != nil {
 				return err
 			}
 			fv.SetInt(int64(d))
 			return nil
 		}
-		i, err := strconv.ParseInt(val, 10, 64)
+		i, err := strconv.ParseInt(val, spec.numberBase(), fv.Type().Bits())
 		if err != nil {
-			return err
+			return numberError(fv.Type(), err)
 		}
 		fv.SetInt(i)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		u, err := strconv.ParseUint(val, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(val, spec.numberBase(), fv.Type().Bits())
 		if err != nil {
-			return err
+			return numberError(fv.Type(), err)
 		}
 		fv.SetUint(u)
 	case reflect.Float32, reflect.Float64:
-		f, err := strconv.ParseFloat(val, 64)
+		f, err := strconv.ParseFloat(val, fv.Type().Bits())
 		if err != nil {
-			return err
+			return numberError(fv.Type(), err)
 		}
 		fv.SetFloat(f)
 	case reflect.Struct:
-		if fv.Type() == reflect.TypeOf(time.Time{}) {
-			t, err := time.Parse(time.RFC3339, val)
-			if err != nil {
-				return err
-			}
-			fv.Set(reflect.ValueOf(t))
-			return nil
-		}
+		return nil
 	case reflect.Slice:
-		parts := strings.Split(val, ",")
+		parts := splitList(val, l.separator(fv.Type(), spec))
 		slice := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
-		for i, s := range parts {
-			s = strings.TrimSpace(s)
-			if err := l.setFieldValue(slice.Index(i), s); err != nil {
-				return err
-			}
+		if err := l.setElements(slice, parts, spec.next()); err != nil {
+			return err
 		}
 		fv.Set(slice)
+	case reflect.Array:
+		parts := splitList(val, l.separator(fv.Type(), spec))
+		if len(parts) != fv.Len() {
+			return fmt.Errorf("got %d elements, want exactly %d", len(parts), fv.Len())
+		}
+		array := reflect.New(fv.Type()).Elem()
+		if err := l.setElements(array, parts, spec.next()); err != nil {
+			return err
+		}
+		fv.Set(array)
+	case reflect.Map:
+		kvSep := spec.keyValueSeparator()
+		entries := make(map[string]string)
+		for _, entry := range splitQuoted(val, l.separator(fv.Type(), spec)) {
+			if entry == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(entry, kvSep)
+			if !ok {
+				return fmt.Errorf("invalid map entry %q: missing %q", entry, kvSep)
+			}
+			entries[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		return l.setMapValues(fv, entries, spec.next())
 	default:
 		return &errUnsupportedKind{fv.Kind()}
 	}
 	return nil
 }
+
+// splitList splits a slice or array value into its elements, either from a
+// JSON array such as ["a,b","c"] or separated by the field separator, where
+// elements may be double-quoted CSV-style to contain the separator.
+func splitList(val, sep string) []string {
+	if parts, ok := splitJSONArray(val); ok {
+		return parts
+	}
+	return splitQuoted(val, sep)
+}
+
+// setElements sets each element of a slice or array from parts.
+func (l *Loader) setElements(list reflect.Value, parts []string, spec fieldSpec) error {
+	for i, s := range parts {
+		if err := l.setValue(list.Index(i), s, spec); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// setMapValues builds a map of the type of fv from raw keys and values,
+// converting both through setFieldValue.
+func (l *Loader) setMapValues(fv reflect.Value, entries map[string]string, spec fieldSpec) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return l.setMapValues(fv.Elem(), entries, spec)
+	}
+
+	if fv.Kind() != reflect.Map {
+		return &errUnsupportedKind{fv.Kind()}
+	}
+
+	mapType := fv.Type()
+	m := reflect.MakeMapWithSize(mapType, len(entries))
+	for k, v := range entries {
+		key := reflect.New(mapType.Key()).Elem()
+		if err := l.setValue(key, k, spec); err != nil {
+			return fmt.Errorf("map key %q: %w", k, err)
+		}
+		elem := reflect.New(mapType.Elem()).Elem()
+		if err := l.setValue(elem, v, spec); err != nil {
+			return fmt.Errorf("map value for %q: %w", k, err)
+		}
+		m.SetMapIndex(key, elem)
+	}
+	fv.Set(m)
+	return nil
+}
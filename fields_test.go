@@ -2,6 +2,7 @@ package autoenv
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -115,6 +116,10 @@ func TestJoinParent(t *testing.T) {
 	}
 }
 
+type BaseTestConfig struct {
+	LogLevel string
+}
+
 func TestGetStructFields(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -173,6 +178,50 @@ func TestGetStructFields(t *testing.T) {
 				{name: "ParentField_ChildField"},
 			},
 		},
+		{
+			name: "nested struct with envPrefix",
+			structType: struct {
+				Database struct {
+					Host string
+				} `envPrefix:"DB_"`
+			}{},
+			parent: "",
+			want: []fieldInfo{
+				{name: "DB_Host"},
+			},
+		},
+		{
+			name: "embedded struct keeps its type name by default",
+			structType: struct {
+				BaseTestConfig
+			}{},
+			parent: "",
+			want: []fieldInfo{
+				{name: "BaseTestConfig_LogLevel"},
+			},
+		},
+		{
+			name: "embedded struct inline",
+			structType: struct {
+				BaseTestConfig `env:",inline"`
+				Port           int
+			}{},
+			parent: "",
+			want: []fieldInfo{
+				{name: "LogLevel"},
+				{name: "Port"},
+			},
+		},
+		{
+			name: "nested struct squash",
+			structType: struct {
+				Base BaseTestConfig `env:",squash"`
+			}{},
+			parent: "",
+			want: []fieldInfo{
+				{name: "LogLevel"},
+			},
+		},
 		{
 			name: "struct with pointer fields",
 			structType: struct {
@@ -245,6 +294,38 @@ func TestResolveFieldName(t *testing.T) {
 			},
 			want: "UnrelatedField",
 		},
+		{
+			name: "Json tag options are stripped",
+			field: reflect.StructField{
+				Name: "Port",
+				Tag:  `json:"port,omitempty"`,
+			},
+			want: "port",
+		},
+		{
+			name: "Env tag options are stripped",
+			field: reflect.StructField{
+				Name: "Port",
+				Tag:  `env:"SERVICE_PORT,required" json:"port,omitempty"`,
+			},
+			want: "SERVICE_PORT",
+		},
+		{
+			name: "Env tag with options only falls back to json name",
+			field: reflect.StructField{
+				Name: "Port",
+				Tag:  `env:",required" json:"port"`,
+			},
+			want: "port",
+		},
+		{
+			name: "Env tag with options only falls back to field name",
+			field: reflect.StructField{
+				Name: "Port",
+				Tag:  `env:",required"`,
+			},
+			want: "Port",
+		},
 		{
 			name: "Empty field name, no tags",
 			field: reflect.StructField{
@@ -300,6 +381,33 @@ func TestShouldIncludeField(t *testing.T) {
 			options:        options{onlyEnvTag: false},
 			expectedResult: false,
 		},
+		{
+			name: "field with env skip marker",
+			field: reflect.StructField{
+				Name: "Skipped",
+				Tag:  `env:"-"`,
+			},
+			options:        options{onlyEnvTag: false},
+			expectedResult: false,
+		},
+		{
+			name: "field with json skip marker",
+			field: reflect.StructField{
+				Name: "Skipped",
+				Tag:  `json:"-"`,
+			},
+			options:        options{onlyEnvTag: false},
+			expectedResult: false,
+		},
+		{
+			name: "field with json skip marker and env name",
+			field: reflect.StructField{
+				Name: "Loaded",
+				Tag:  `env:"LOADED" json:"-"`,
+			},
+			options:        options{onlyEnvTag: false},
+			expectedResult: true,
+		},
 		{
 			name: "field with no env tag and onlyEnvTag disabled",
 			field: reflect.StructField{
@@ -398,3 +506,305 @@ func TestSetFieldValue(t *testing.T) {
 		})
 	}
 }
+
+func TestSetFieldValueMap(t *testing.T) {
+	loader := NewLoader()
+
+	tests := []struct {
+		name    string
+		field   reflect.Value
+		value   string
+		want    any
+		wantErr bool
+	}{
+		{
+			name:  "map of strings",
+			field: reflect.New(reflect.TypeOf(map[string]string{})).Elem(),
+			value: "team:core, tier:1",
+			want:  map[string]string{"team": "core", "tier": "1"},
+		},
+		{
+			name:  "typed keys and values",
+			field: reflect.New(reflect.TypeOf(map[int]time.Duration{})).Elem(),
+			value: "1:1s,2:2m",
+			want:  map[int]time.Duration{1: time.Second, 2: 2 * time.Minute},
+		},
+		{
+			name:  "value containing separator",
+			field: reflect.New(reflect.TypeOf(map[string]string{})).Elem(),
+			value: "addr:localhost:8080",
+			want:  map[string]string{"addr": "localhost:8080"},
+		},
+		{
+			name:  "pointer to map",
+			field: reflect.New(reflect.TypeOf(&map[string]int{})).Elem(),
+			value: "a:1",
+			want:  &map[string]int{"a": 1},
+		},
+		{
+			name:    "entry without separator",
+			field:   reflect.New(reflect.TypeOf(map[string]string{})).Elem(),
+			value:   "team",
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			field:   reflect.New(reflect.TypeOf(map[string]int{})).Elem(),
+			value:   "a:one",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loader.setFieldValue(tt.field, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("setFieldValue() = got no error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setFieldValue() = unexpected error: %v", err)
+			}
+			if got := tt.field.Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setFieldValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFieldSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		field reflect.StructField
+		want  fieldSpec
+	}{
+		{
+			name:  "no tags",
+			field: reflect.StructField{Name: "Port"},
+			want:  fieldSpec{},
+		},
+		{
+			name: "env options",
+			field: reflect.StructField{
+				Name: "DSN",
+				Tag:  `env:"DB_URL,required,notEmpty,unset,file,expand,secret,sep=;"`,
+			},
+			want: fieldSpec{required: true, notEmpty: true, unset: true, file: true, expand: true, secret: true, seps: []string{";"}},
+		},
+		{
+			name: "default option",
+			field: reflect.StructField{
+				Name: "Port",
+				Tag:  `env:"PORT,default=8080"`,
+			},
+			want: fieldSpec{defaultValue: "8080", hasDefault: true},
+		},
+		{
+			name: "default tag wins over default option",
+			field: reflect.StructField{
+				Name: "Hosts",
+				Tag:  `env:"HOSTS,default=a" default:"a,b"`,
+			},
+			want: fieldSpec{defaultValue: "a,b", hasDefault: true},
+		},
+		{
+			name: "standalone tags",
+			field: reflect.StructField{
+				Name: "Name",
+				Tag:  `required:"true" allowEmpty:"true"`,
+			},
+			want: fieldSpec{required: true, allowEmpty: true},
+		},
+		{
+			name: "disabled standalone tags",
+			field: reflect.StructField{
+				Name: "Name",
+				Tag:  `required:"false" allowEmpty:"no"`,
+			},
+			want: fieldSpec{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseFieldSpec(tt.field, resolveFieldTag(tt.field))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFieldSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetValueNumbers(t *testing.T) {
+	loader := NewLoader()
+
+	tests := []struct {
+		name         string
+		field        reflect.Value
+		value        string
+		spec         fieldSpec
+		want         any
+		wantOverflow bool
+		wantErr      bool
+	}{
+		{
+			name:  "int8 in range",
+			field: reflect.New(reflect.TypeOf(int8(0))).Elem(),
+			value: "-128",
+			want:  int8(-128),
+		},
+		{
+			name:         "int8 overflow",
+			field:        reflect.New(reflect.TypeOf(int8(0))).Elem(),
+			value:        "300",
+			wantOverflow: true,
+		},
+		{
+			name:         "uint16 overflow",
+			field:        reflect.New(reflect.TypeOf(uint16(0))).Elem(),
+			value:        "70000",
+			wantOverflow: true,
+		},
+		{
+			name:         "float32 overflow",
+			field:        reflect.New(reflect.TypeOf(float32(0))).Elem(),
+			value:        "1e40",
+			wantOverflow: true,
+		},
+		{
+			name:  "float32 in range",
+			field: reflect.New(reflect.TypeOf(float32(0))).Elem(),
+			value: "1.5",
+			want:  float32(1.5),
+		},
+		{
+			name:    "hex without base option",
+			field:   reflect.New(reflect.TypeOf(0)).Elem(),
+			value:   "0x1F",
+			wantErr: true,
+		},
+		{
+			name:  "hex with base 0",
+			field: reflect.New(reflect.TypeOf(0)).Elem(),
+			value: "0x1F",
+			spec:  fieldSpec{hasBase: true},
+			want:  31,
+		},
+		{
+			name:  "binary and underscores with base 0",
+			field: reflect.New(reflect.TypeOf(uint32(0))).Elem(),
+			value: "0b1000_0001",
+			spec:  fieldSpec{hasBase: true},
+			want:  uint32(129),
+		},
+		{
+			name:  "explicit base 16",
+			field: reflect.New(reflect.TypeOf(int64(0))).Elem(),
+			value: "ff",
+			spec:  fieldSpec{base: 16, hasBase: true},
+			want:  int64(255),
+		},
+		{
+			name:  "octal in slice with base 0",
+			field: reflect.New(reflect.TypeOf([]int{})).Elem(),
+			value: "0o755, 1_000",
+			spec:  fieldSpec{hasBase: true},
+			want:  []int{493, 1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loader.setValue(tt.field, tt.value, tt.spec)
+			if tt.wantOverflow {
+				if !IsOverflowError(err) {
+					t.Errorf("setValue() = %v, want *OverflowError", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("setValue() = got no error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setValue() = unexpected error: %v", err)
+			}
+			if got := tt.field.Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetValueArrays(t *testing.T) {
+	loader := NewLoader()
+
+	tests := []struct {
+		name    string
+		field   reflect.Value
+		value   string
+		spec    fieldSpec
+		want    any
+		wantErr string
+	}{
+		{
+			name:  "rgb color",
+			field: reflect.New(reflect.TypeOf([3]uint8{})).Elem(),
+			value: "255, 128, 0",
+			want:  [3]uint8{255, 128, 0},
+		},
+		{
+			name:  "replica weights",
+			field: reflect.New(reflect.TypeOf([2]float64{})).Elem(),
+			value: "0.75;0.25",
+			spec:  fieldSpec{seps: []string{";"}},
+			want:  [2]float64{0.75, 0.25},
+		},
+		{
+			name:  "pointer to array",
+			field: reflect.New(reflect.TypeOf(&[2]string{})).Elem(),
+			value: "a,b",
+			want:  &[2]string{"a", "b"},
+		},
+		{
+			name:    "too few elements",
+			field:   reflect.New(reflect.TypeOf([3]int{})).Elem(),
+			value:   "1,2",
+			wantErr: "got 2 elements, want exactly 3",
+		},
+		{
+			name:    "too many elements",
+			field:   reflect.New(reflect.TypeOf([2]int{})).Elem(),
+			value:   "1,2,3",
+			wantErr: "got 3 elements, want exactly 2",
+		},
+		{
+			name:    "invalid element",
+			field:   reflect.New(reflect.TypeOf([2]int{})).Elem(),
+			value:   "1,x",
+			wantErr: "element 1:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loader.setValue(tt.field, tt.value, tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("setValue() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setValue() = unexpected error: %v", err)
+			}
+			if got := tt.field.Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
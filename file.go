@@ -5,17 +5,18 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 )
 
-func (l *Loader) loadEnvFile(path string) error {
+func (l *Loader) loadEnvFile(path string) (map[string]string, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	f, err := os.Open(absPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func(f *os.File) {
 		if err := f.Close(); err != nil {
@@ -23,6 +24,7 @@ func (l *Loader) loadEnvFile(path string) error {
 		}
 	}(f)
 
+	values := make(map[string]string)
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := scanner.Bytes()
@@ -55,11 +57,35 @@ func (l *Loader) loadEnvFile(path string) error {
 			}
 		}
 
-		if err := os.Setenv(key, string(val)); err != nil {
+		values[key] = string(val)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// readValueFile reads a value stored in a file, dropping the trailing
+// newline editors and secret managers usually add.
+func readValueFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	val := strings.TrimSuffix(string(b), "\n")
+	return strings.TrimSuffix(val, "\r"), nil
+}
+
+func exportEnv(values map[string]string, overwrite bool) error {
+	for key, val := range values {
+		if _, ok := os.LookupEnv(key); ok && !overwrite {
+			continue
+		}
+		if err := os.Setenv(key, val); err != nil {
 			return err
 		}
 	}
-	return scanner.Err()
+	return nil
 }
 
 func trimSpaces(b []byte) []byte {
@@ -3,6 +3,7 @@ package autoenv
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -118,7 +119,7 @@ func TestLoadEnvFile(t *testing.T) {
 			}
 
 			loader := &Loader{}
-			err = loader.loadEnvFile(tmpFile.Name())
+			got, err := loader.loadEnvFile(tmpFile.Name())
 
 			if tt.expectError {
 				if err == nil {
@@ -132,9 +133,58 @@ func TestLoadEnvFile(t *testing.T) {
 			}
 
 			for key, want := range tt.expectedEnv {
-				if got := os.Getenv(key); got != want {
-					t.Errorf("loadEnvFile() = key, %q got %q, want %q", key, got, want)
+				if got[key] != want {
+					t.Errorf("loadEnvFile() = key, %q got %q, want %q", key, got[key], want)
 				}
+				if _, ok := os.LookupEnv(key); ok {
+					t.Errorf("loadEnvFile() = key %q leaked into the process environment", key)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadEnvFilesExport(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		export bool
+	}{
+		{
+			name:   "OverlayOnly",
+			opts:   nil,
+			export: false,
+		},
+		{
+			name:   "WithExport",
+			opts:   []Option{WithExport()},
+			export: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(path, []byte("AUTOENV_EXPORT_TEST=value\n"), 0o600); err != nil {
+				t.Fatalf("failed to write env file: %v", err)
+			}
+			t.Cleanup(func() {
+				_ = os.Unsetenv("AUTOENV_EXPORT_TEST")
+			})
+
+			var cfg struct {
+				AutoenvExportTest string
+			}
+			loader := NewLoader(append(tt.opts, WithPaths([]string{path}))...)
+			if err := loader.Load(&cfg); err != nil {
+				t.Fatalf("Load() = unexpected error: %v", err)
+			}
+
+			if cfg.AutoenvExportTest != "value" {
+				t.Errorf("Load() = got %q, want %q", cfg.AutoenvExportTest, "value")
+			}
+			if _, ok := os.LookupEnv("AUTOENV_EXPORT_TEST"); ok != tt.export {
+				t.Errorf("Load() = exported %v, want %v", ok, tt.export)
 			}
 		})
 	}
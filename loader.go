@@ -2,9 +2,11 @@ package autoenv
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -32,11 +34,11 @@ func (l *Loader) Load(i any) error {
 		l.options.logger.DebugF("loading struct %T", i)
 	}
 
-	l.loadEnvFiles()
+	env := l.newEnvironment()
 
 	t := reflect.TypeOf(i)
 	fields := l.getStructFields(t, "")
-	return l.mapEnvValues(reflect.ValueOf(i), fields)
+	return l.mapEnvValues(reflect.ValueOf(i), fields, env)
 }
 
 func (l *Loader) isVerbose() bool {
@@ -55,6 +57,14 @@ func (l *Loader) isOnlyEnvTag() bool {
 	return l.options.onlyEnvTag
 }
 
+func (l *Loader) allowsEmpty(fi fieldInfo) bool {
+	if l == nil {
+		return fi.spec.allowEmpty
+	}
+
+	return l.options.allowEmpty || fi.spec.allowEmpty
+}
+
 func (l *Loader) isIgnoring(field, parent string) bool {
 	if l == nil {
 		return false
@@ -69,54 +79,406 @@ func (l *Loader) isIgnoring(field, parent string) bool {
 	})
 }
 
-func (l *Loader) loadEnvFiles() {
+func (l *Loader) loadEnvFiles() map[string]string {
 	if !l.options.withFiles {
-		return
+		return nil
 	}
 
+	values := make(map[string]string)
 	for _, fileName := range l.options.filesPaths {
-		if err := l.loadEnvFile(fileName); err != nil {
+		fileValues, err := l.loadEnvFile(fileName)
+		if err != nil {
 			l.options.logger.ErrorF("failed to load file: %s", err)
-			return
+			break
 		}
+		maps.Copy(values, fileValues)
 
 		if l.isVerbose() {
 			l.options.logger.DebugF("loaded file: %s", fileName)
 		}
 	}
+
+	if l.options.export {
+		if err := exportEnv(values, l.filesOverEnv()); err != nil {
+			l.options.logger.ErrorF("failed to export file values: %s", err)
+		}
+	}
+
+	return values
+}
+
+func (l *Loader) mapEnvValues(target reflect.Value, fields []fieldInfo, env environment) error {
+	errs := &loadErrors{}
+	l.mapFields(target, fields, env, errs)
+	return errs.err()
 }
 
-func (l *Loader) mapEnvValues(target reflect.Value, fields []fieldInfo) error {
+// loadErrors collects failures across every field, including the elements
+// of struct slices, so they can be reported at once.
+type loadErrors struct {
+	failed  []*FieldError
+	missing []MissingField
+}
+
+func (e *loadErrors) fail(fi fieldInfo, key string, err error) {
+	if fi.spec.secret {
+		err = &secretError{err: err}
+	}
+	e.failed = append(e.failed, &FieldError{
+		Field: fi.path,
+		Key:   key,
+		Type:  fi.field.Type,
+		Err:   err,
+	})
+}
+
+func (e *loadErrors) err() error {
+	var missingErr *MissingError
+	if len(e.missing) > 0 {
+		missingErr = &MissingError{Fields: e.missing}
+	}
+
+	if len(e.failed) > 0 {
+		return &LoadError{Fields: e.failed, Missing: missingErr}
+	}
+	if missingErr != nil {
+		return missingErr
+	}
+	return nil
+}
+
+func (l *Loader) mapFields(target reflect.Value, fields []fieldInfo, env environment, errs *loadErrors) {
 	if target.Kind() == reflect.Ptr {
 		target = target.Elem()
 	}
 
+	l.allocPresentStructs(target, fields, env)
+
 	for _, fi := range fields {
 		key := l.getEnvKey(fi.name)
 		if key == "" {
 			continue
 		}
 
-		val := os.Getenv(key)
-		if val == "" {
+		fv, ok := fieldByIndex(target, fi.field.Index, false)
+		if !ok {
+			continue
+		}
+
+		if fi.elemFields != nil {
+			l.mapStructSlice(fv, fi, key, env, errs)
 			continue
 		}
 
-		fv := target.FieldByIndex(fi.field.Index)
+		spec := fi.spec
+		val, ok := l.lookupField(fi, key, env)
+		if spec.unset {
+			l.unsetEnv(key)
+		}
+
+		// An empty variable falls back to KEY_FILE like a missing one, so both
+		// paths treat empty values the same.
+		fromFile := false
+		if !ok || (val == "" && !l.allowsEmpty(fi)) {
+			if path, found := l.lookupFileVar(key, fields, env); found {
+				if spec.unset {
+					l.unsetEnv(key + l.options.fileSuffix)
+				}
+				content, err := readValueFile(path)
+				if err != nil {
+					errs.fail(fi, key+l.options.fileSuffix, err)
+					continue
+				}
+				val, ok, fromFile = content, true, true
+			}
+		}
+
+		if ok && val == "" {
+			if spec.notEmpty {
+				errs.fail(fi, key, ErrEmptyValue)
+				continue
+			}
+			if !l.allowsEmpty(fi) {
+				ok = false
+			}
+		}
+
+		var scanned map[string]string
+		if !ok && l.isScannableMap(fi) {
+			scanned = l.scanMap(fi, key, fields, env)
+		}
+
+		usingDefault := false
+		if !ok && len(scanned) == 0 {
+			if !spec.hasDefault {
+				if spec.required {
+					errs.missing = append(errs.missing, MissingField{Key: key, Field: fi.path})
+				}
+				continue
+			}
+			val = spec.defaultValue
+			usingDefault = true
+		}
+
+		if spec.expand {
+			val = env.expand(val)
+		}
+
+		if spec.file && val != "" && !fromFile {
+			content, err := readValueFile(val)
+			if err != nil {
+				errs.fail(fi, key, err)
+				continue
+			}
+			val = content
+		}
+
 		if !fv.CanSet() {
 			continue
 		}
 
-		if err := l.setFieldValue(fv, val); err != nil {
-			return err
+		var err error
+		switch {
+		case len(scanned) > 0:
+			err = l.setMapValues(fv, scanned, spec.next())
+		case val == "":
+			setEmptyValue(fv)
+		case spec.json:
+			err = decodeJSON(fv, val)
+		default:
+			err = l.setValue(fv, val, spec)
+		}
+		if err != nil {
+			errs.fail(fi, key, err)
+			continue
 		}
 
 		if l.isVerbose() {
-			l.options.logger.DebugF("loaded %s as %s (%s)", fi.name, key, fi.field.Type.String())
+			if usingDefault {
+				l.options.logger.DebugF("using default for %s as %s (%s)", fi.name, key, fi.field.Type.String())
+			} else if fromFile {
+				l.options.logger.DebugF("loaded %s from file in %s%s (%s)", fi.name, key, l.options.fileSuffix, fi.field.Type.String())
+			} else if len(scanned) > 0 {
+				l.options.logger.DebugF("loaded %s from %d %s_* variables (%s)", fi.name, len(scanned), key, fi.field.Type.String())
+			} else {
+				l.options.logger.DebugF("loaded %s as %s (%s)", fi.name, key, fi.field.Type.String())
+			}
 		}
 	}
+}
 
-	return nil
+// allocPresentStructs allocates the nil struct pointers leading to fields
+// whose variables are present. Pointers without any variable stay nil, so
+// their fields, defaults and required checks are skipped entirely.
+func (l *Loader) allocPresentStructs(target reflect.Value, fields []fieldInfo, env environment) {
+	for _, fi := range fields {
+		if _, ok := fieldByIndex(target, fi.field.Index, false); ok {
+			continue
+		}
+
+		key := l.getEnvKey(fi.name)
+		if key == "" || !l.isPresent(fi, key, fields, env) {
+			continue
+		}
+
+		fieldByIndex(target, fi.field.Index, true)
+		if l.isVerbose() {
+			l.options.logger.DebugF("%s: allocating parent struct (%s is set)", fi.path, key)
+		}
+	}
+}
+
+// isPresent reports whether any variable for the field is set.
+func (l *Loader) isPresent(fi fieldInfo, key string, fields []fieldInfo, env environment) bool {
+	if fi.elemFields != nil {
+		n, err := countIndexedKeys(env.scan(key + "_"))
+		return n > 0 || err != nil
+	}
+
+	if val, ok := l.lookupField(fi, key, env); ok && (val != "" || fi.spec.notEmpty || l.allowsEmpty(fi)) {
+		return true
+	}
+
+	if _, ok := l.lookupFileVar(key, fields, env); ok {
+		return true
+	}
+
+	return l.isScannableMap(fi) && len(l.scanMap(fi, key, fields, env)) > 0
+}
+
+// lookupField resolves the variable of a field. Empty values only shadow
+// later sources when the field allows them.
+func (l *Loader) lookupField(fi fieldInfo, key string, env environment) (string, bool) {
+	if l.allowsEmpty(fi) {
+		return env.lookupFirst(key)
+	}
+	return env.lookup(key)
+}
+
+// lookupFileVar resolves the KEY_FILE variable holding the path of a file
+// with the value of KEY, when the loader was built WithFileSuffix. It is
+// skipped when KEY_FILE is the key of another field, such as LogFile next
+// to Log, which holds a path of its own.
+func (l *Loader) lookupFileVar(key string, fields []fieldInfo, env environment) (string, bool) {
+	if l.options.fileSuffix == "" {
+		return "", false
+	}
+
+	fileKey := key + l.options.fileSuffix
+	if slices.ContainsFunc(fields, func(fi fieldInfo) bool {
+		return l.getEnvKey(fi.name) == fileKey
+	}) {
+		return "", false
+	}
+
+	path, ok := env.lookup(fileKey)
+	return path, ok && path != ""
+}
+
+// fieldByIndex works like reflect.Value.FieldByIndex but reports false instead
+// of panicking on nil struct pointers, or allocates them when alloc is set.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func (l *Loader) unsetEnv(key string) {
+	if err := os.Unsetenv(key); err != nil {
+		l.options.logger.ErrorF("failed to unset %s: %s", key, err)
+	}
+}
+
+// mapStructSlice loads a slice of structs from indexed keys such as
+// KEY_0_HOST and KEY_1_HOST, the highest index found sets the length.
+func (l *Loader) mapStructSlice(fv reflect.Value, fi fieldInfo, key string, env environment, errs *loadErrors) {
+	n, err := countIndexedKeys(env.scan(key + "_"))
+	if err != nil {
+		errs.fail(fi, key+"_*", err)
+		return
+	}
+	if n == 0 {
+		if fi.spec.required {
+			errs.missing = append(errs.missing, MissingField{Key: key + "_0_*", Field: fi.path})
+		}
+		return
+	}
+
+	if !fv.CanSet() {
+		return
+	}
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+
+	slice := reflect.MakeSlice(fv.Type(), n, n)
+	for i := 0; i < n; i++ {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elem.Type().Elem()))
+		}
+
+		index := strconv.Itoa(i)
+		elemFields := make([]fieldInfo, len(fi.elemFields))
+		for j, ef := range fi.elemFields {
+			ef.name = fi.name + "_" + index + "_" + ef.name
+			ef.path = fi.path + "[" + index + "]" + fieldPathSeparator + ef.path
+			elemFields[j] = ef
+		}
+		l.mapFields(elem, elemFields, env, errs)
+	}
+	fv.Set(slice)
+
+	if l.isVerbose() {
+		l.options.logger.DebugF("loaded %s from %d indexed %s_* elements (%s)", fi.name, n, key, fi.field.Type.String())
+	}
+}
+
+// countIndexedKeys returns the number of distinct leading indexes of the
+// given KEY_<index>_* suffixes, which must be contiguous from 0 so a stray
+// variable cannot inflate the slice.
+func countIndexedKeys(suffixes map[string]string) (int, error) {
+	indexes := make(map[int]struct{})
+	highest := -1
+	for suffix := range suffixes {
+		index, _, ok := strings.Cut(suffix, "_")
+		if !ok {
+			continue
+		}
+		// Only canonical indexes such as 1, not +1 or 01, are the ones
+		// mapStructSlice looks up.
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 || strconv.Itoa(i) != index {
+			continue
+		}
+		indexes[i] = struct{}{}
+		highest = max(highest, i)
+	}
+
+	if highest >= len(indexes) {
+		return 0, fmt.Errorf("%w: found index %d with %d element(s)", ErrSparseIndex, highest, len(indexes))
+	}
+	return len(indexes), nil
+}
+
+// scanMap collects the KEY_* variables of a map field, leaving out those
+// belonging to the sibling fields whose keys share the KEY_ prefix, and
+// empty values unless the field allows them.
+func (l *Loader) scanMap(fi fieldInfo, key string, fields []fieldInfo, env environment) map[string]string {
+	values := env.scan(key + "_")
+	for _, other := range fields {
+		suffix, ok := strings.CutPrefix(l.getEnvKey(other.name), key+"_")
+		if !ok || suffix == "" {
+			continue
+		}
+
+		delete(values, suffix)
+		if l.options.fileSuffix != "" {
+			delete(values, suffix+l.options.fileSuffix)
+		}
+		if other.elemFields != nil || l.isScannableMap(other) {
+			maps.DeleteFunc(values, func(name, _ string) bool {
+				return strings.HasPrefix(name, suffix+"_")
+			})
+		}
+	}
+
+	if !l.allowsEmpty(fi) {
+		maps.DeleteFunc(values, func(_, val string) bool {
+			return val == ""
+		})
+	}
+	return values
+}
+
+// isScannableMap reports whether a missing variable for the field should be
+// resolved by collecting every KEY_* variable into the map.
+func (l *Loader) isScannableMap(fi fieldInfo) bool {
+	if fi.spec.json {
+		return false
+	}
+
+	t := l.getFieldType(fi.field.Type)
+	if t.Kind() != reflect.Map {
+		return false
+	}
+	if _, ok := l.options.parsers[t]; ok {
+		return false
+	}
+	return !implementsDecoder(reflect.PointerTo(t))
 }
 
 func (l *Loader) getEnvKey(name string) string {
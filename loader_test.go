@@ -0,0 +1,838 @@
+package autoenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) InfoF(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) WarnF(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) DebugF(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) ErrorF(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLoadDefaults(t *testing.T) {
+	type Config struct {
+		Port    int      `default:"8080"`
+		Host    string   `default:"localhost"`
+		Tags    []string `default:"a,b"`
+		Timeout *int     `default:"30"`
+		Name    string
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Config
+	}{
+		{
+			name: "defaults applied when unset",
+			env:  nil,
+			want: Config{Port: 8080, Host: "localhost", Tags: []string{"a", "b"}, Timeout: ptr(30)},
+		},
+		{
+			name: "environment overrides defaults",
+			env:  map[string]string{"PORT": "9090", "TAGS": "c", "NAME": "svc"},
+			want: Config{Port: 9090, Host: "localhost", Tags: []string{"c"}, Timeout: ptr(30), Name: "svc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var got Config
+			if err := NewLoader().Load(&got); err != nil {
+				t.Fatalf("Load() = unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDefaultsVerbose(t *testing.T) {
+	logger := &recordingLogger{}
+	var cfg struct {
+		DefaultedPort int `default:"8080"`
+	}
+
+	if err := NewLoader(WithVerbose(), WithLogger(logger)).Load(&cfg); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	found := false
+	for _, line := range logger.lines {
+		if strings.HasPrefix(line, "using default for DefaultedPort") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Load() = missing \"using default\" log line in %q", logger.lines)
+	}
+}
+
+func TestLoadDefaultInvalid(t *testing.T) {
+	var cfg struct {
+		InvalidDefault int `default:"eighty"`
+	}
+
+	if err := NewLoader().Load(&cfg); err == nil {
+		t.Errorf("Load() = got no error, want error")
+	}
+}
+
+func TestLoadEmptyDefault(t *testing.T) {
+	type Config struct {
+		Port int      `default:""`
+		Tags []string `env:"TAGS,default="`
+		Name string   `default:""`
+	}
+
+	got := Config{Port: 7, Tags: []string{"a"}, Name: "preset"}
+	if err := NewLoader(WithPrefix("EMPTY_DEFAULT")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{Port: 0, Tags: []string{}, Name: ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadRequired(t *testing.T) {
+	type Database struct {
+		DSN  string `required:"true"`
+		Pool int    `required:"false"`
+	}
+	type Config struct {
+		Port     int    `required:"true"`
+		Host     string `required:"true" default:"localhost"`
+		Database Database
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		missing []MissingField
+	}{
+		{
+			name: "all missing reported at once",
+			env:  nil,
+			missing: []MissingField{
+				{Key: "REQ_PORT", Field: "Port"},
+				{Key: "REQ_DATABASE_DSN", Field: "Database.DSN"},
+			},
+		},
+		{
+			name: "partially missing",
+			env:  map[string]string{"REQ_PORT": "8080"},
+			missing: []MissingField{
+				{Key: "REQ_DATABASE_DSN", Field: "Database.DSN"},
+			},
+		},
+		{
+			name:    "all present",
+			env:     map[string]string{"REQ_PORT": "8080", "REQ_DATABASE_DSN": "postgres://"},
+			missing: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var cfg Config
+			err := NewLoader(WithPrefix("REQ")).Load(&cfg)
+			if tt.missing == nil {
+				if err != nil {
+					t.Fatalf("Load() = unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !IsMissingError(err) {
+				t.Fatalf("Load() = %v, want *MissingError", err)
+			}
+			var missingErr *MissingError
+			errors.As(err, &missingErr)
+			if !reflect.DeepEqual(missingErr.Fields, tt.missing) {
+				t.Errorf("Load() missing = %v, want %v", missingErr.Fields, tt.missing)
+			}
+		})
+	}
+}
+
+func TestLoadErrorAggregation(t *testing.T) {
+	t.Setenv("AGG_PORT", "eighty")
+	t.Setenv("AGG_DEBUG", "maybe")
+	t.Setenv("AGG_COMPLEX", "1+2i")
+
+	var cfg struct {
+		Port    int
+		Debug   bool
+		Complex complex128
+		Name    string `required:"true"`
+	}
+	err := NewLoader(WithPrefix("AGG")).Load(&cfg)
+
+	if !IsLoadError(err) {
+		t.Fatalf("Load() = %v, want *LoadError", err)
+	}
+	var loadErr *LoadError
+	errors.As(err, &loadErr)
+
+	wantFields := []string{"Port", "Debug", "Complex"}
+	gotFields := make([]string, len(loadErr.Fields))
+	for i, f := range loadErr.Fields {
+		gotFields[i] = f.Field
+	}
+	if !reflect.DeepEqual(gotFields, wantFields) {
+		t.Errorf("Load() fields = %v, want %v", gotFields, wantFields)
+	}
+	if loadErr.Fields[0].Key != "AGG_PORT" || loadErr.Fields[0].Type != reflect.TypeOf(0) {
+		t.Errorf("Load() first field = %+v, want key AGG_PORT of type int", loadErr.Fields[0])
+	}
+
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("errors.Is(err, strconv.ErrSyntax) = false, want true")
+	}
+	if !IsUnsupportedKindError(err) {
+		t.Errorf("IsUnsupportedKindError(err) = false, want true")
+	}
+	if !IsMissingError(err) {
+		t.Errorf("IsMissingError(err) = false, want true")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{"failed to load 3 field(s):", "Port (AGG_PORT, int)", "AGG_NAME (Name)"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error() = %q, want it to contain %q", msg, want)
+		}
+	}
+}
+
+func TestLoadEmptyValues(t *testing.T) {
+	type Config struct {
+		Host    string   `default:"localhost"`
+		Port    int      `default:"8080"`
+		Tags    []string `default:"a,b"`
+		Level   *int     `default:"3"`
+		Cleared string   `default:"keep" allowEmpty:"true"`
+	}
+
+	tests := []struct {
+		name    string
+		options []Option
+		env     map[string]string
+		want    Config
+	}{
+		{
+			name:    "empty treated as unset by default",
+			options: nil,
+			env:     map[string]string{"EMPTY_HOST": "", "EMPTY_PORT": "", "EMPTY_TAGS": "", "EMPTY_LEVEL": ""},
+			want:    Config{Host: "localhost", Port: 8080, Tags: []string{"a", "b"}, Level: ptr(3), Cleared: "keep"},
+		},
+		{
+			name:    "allowEmpty tag clears the default",
+			options: nil,
+			env:     map[string]string{"EMPTY_CLEARED": ""},
+			want:    Config{Host: "localhost", Port: 8080, Tags: []string{"a", "b"}, Level: ptr(3), Cleared: ""},
+		},
+		{
+			name:    "WithAllowEmpty sets zero values",
+			options: []Option{WithAllowEmpty()},
+			env:     map[string]string{"EMPTY_HOST": "", "EMPTY_PORT": "", "EMPTY_TAGS": "", "EMPTY_LEVEL": ""},
+			want:    Config{Host: "", Port: 0, Tags: []string{}, Level: nil, Cleared: "keep"},
+		},
+		{
+			name:    "WithAllowEmpty leaves unset fields to defaults",
+			options: []Option{WithAllowEmpty()},
+			env:     nil,
+			want:    Config{Host: "localhost", Port: 8080, Tags: []string{"a", "b"}, Level: ptr(3), Cleared: "keep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var got Config
+			if err := NewLoader(append(tt.options, WithPrefix("EMPTY"))...).Load(&got); err != nil {
+				t.Fatalf("Load() = unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEmptyLeavesUnsetUntouched(t *testing.T) {
+	t.Setenv("UNTOUCHED_NAME", "")
+
+	cfg := struct {
+		Name  string
+		Count int
+	}{Name: "preset", Count: 7}
+	if err := NewLoader(WithAllowEmpty(), WithPrefix("UNTOUCHED")).Load(&cfg); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+	if cfg.Name != "" || cfg.Count != 7 {
+		t.Errorf("Load() = %+v, want Name cleared and Count untouched", cfg)
+	}
+}
+
+func TestLoadMapScan(t *testing.T) {
+	t.Setenv("SCAN_LABELS", "team:core,tier:1")
+	t.Setenv("SCAN_LIMITS_TENANT_A", "10")
+	t.Setenv("SCAN_LIMITS_TENANT_B", "20")
+	t.Setenv("SCAN_WEIGHTS_X", "heavy")
+
+	var cfg struct {
+		Labels  map[string]string
+		Limits  map[string]int
+		Weights map[string]int
+		Empty   map[string]string
+	}
+	err := NewLoader(WithPrefix("SCAN")).Load(&cfg)
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || len(loadErr.Fields) != 1 || loadErr.Fields[0].Key != "SCAN_WEIGHTS" {
+		t.Fatalf("Load() = %v, want a single SCAN_WEIGHTS failure", err)
+	}
+	if want := map[string]string{"team": "core", "tier": "1"}; !reflect.DeepEqual(cfg.Labels, want) {
+		t.Errorf("Labels = %v, want %v", cfg.Labels, want)
+	}
+	if want := map[string]int{"TENANT_A": 10, "TENANT_B": 20}; !reflect.DeepEqual(cfg.Limits, want) {
+		t.Errorf("Limits = %v, want %v", cfg.Limits, want)
+	}
+	if cfg.Empty != nil {
+		t.Errorf("Empty = %v, want nil", cfg.Empty)
+	}
+}
+
+func TestLoadMapScanNested(t *testing.T) {
+	t.Setenv("NESTED_SCAN_GROUPS_ADMINS", "alice/bob")
+	t.Setenv("NESTED_SCAN_GROUPS_OPS", "carol")
+	t.Setenv("NESTED_SCAN_SHARDS_EU", "1,2;3")
+
+	var got struct {
+		Groups map[string][]string `sep:"| /"`
+		Shards map[string][][]int
+	}
+	if err := NewLoader(WithPrefix("NESTED_SCAN")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	if want := map[string][]string{"ADMINS": {"alice", "bob"}, "OPS": {"carol"}}; !reflect.DeepEqual(got.Groups, want) {
+		t.Errorf("Groups = %v, want %v", got.Groups, want)
+	}
+	if want := map[string][][]int{"EU": {{1, 2}, {3}}}; !reflect.DeepEqual(got.Shards, want) {
+		t.Errorf("Shards = %v, want %v", got.Shards, want)
+	}
+}
+
+func TestLoadMapScanSiblings(t *testing.T) {
+	t.Setenv("SIBLING_LABELS_EXTRA", "zzz")
+	t.Setenv("SIBLING_LABELS_TEAM", "core")
+	t.Setenv("SIBLING_LABELS_DB_HOST", "db.local")
+	t.Setenv("SIBLING_LABELS_ROUTES_0_HOST", "a.local")
+	t.Setenv("SIBLING_LIMITS_A", "")
+	t.Setenv("SIBLING_LIMITS_B", "1")
+	t.Setenv("SIBLING_CLEARED_A", "")
+
+	type Route struct {
+		Host string
+	}
+	type Config struct {
+		Labels       map[string]string
+		LabelsExtra  string
+		LabelsDB     struct{ Host string } `envPrefix:"LABELS_DB_"`
+		LabelsRoutes []Route
+		Limits       map[string]int
+		Cleared      map[string]string `allowEmpty:"true"`
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("SIBLING")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	if want := map[string]string{"TEAM": "core"}; !reflect.DeepEqual(got.Labels, want) {
+		t.Errorf("Labels = %v, want %v", got.Labels, want)
+	}
+	if got.LabelsExtra != "zzz" || got.LabelsDB.Host != "db.local" || len(got.LabelsRoutes) != 1 {
+		t.Errorf("Load() = %+v, want sibling fields loaded", got)
+	}
+	if want := map[string]int{"B": 1}; !reflect.DeepEqual(got.Limits, want) {
+		t.Errorf("Limits = %v, want %v", got.Limits, want)
+	}
+	if want := map[string]string{"A": ""}; !reflect.DeepEqual(got.Cleared, want) {
+		t.Errorf("Cleared = %v, want %v", got.Cleared, want)
+	}
+}
+
+func TestLoadStructSlice(t *testing.T) {
+	type Upstream struct {
+		Host   string `required:"true"`
+		Port   int    `default:"80"`
+		Labels []string
+	}
+	type Config struct {
+		Upstreams []Upstream
+		Backups   []*Upstream
+		Proxy     struct {
+			Routes []Upstream
+		}
+		Unused []Upstream
+	}
+
+	t.Setenv("IDX_UPSTREAMS_0_HOST", "a.local")
+	t.Setenv("IDX_UPSTREAMS_0_PORT", "8080")
+	t.Setenv("IDX_UPSTREAMS_1_HOST", "b.local")
+	t.Setenv("IDX_UPSTREAMS_1_LABELS", "x,y")
+	t.Setenv("IDX_BACKUPS_0_HOST", "backup.local")
+	t.Setenv("IDX_PROXY_ROUTES_0_HOST", "route.local")
+
+	var got Config
+	if err := NewLoader(WithPrefix("IDX")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		Upstreams: []Upstream{
+			{Host: "a.local", Port: 8080},
+			{Host: "b.local", Port: 80, Labels: []string{"x", "y"}},
+		},
+		Backups: []*Upstream{{Host: "backup.local", Port: 80}},
+	}
+	want.Proxy.Routes = []Upstream{{Host: "route.local", Port: 80}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadStructSliceErrors(t *testing.T) {
+	type Upstream struct {
+		Host string `required:"true"`
+		Port int
+	}
+
+	t.Setenv("IDXERR_UPSTREAMS_0_PORT", "eighty")
+	t.Setenv("IDXERR_UPSTREAMS_1_HOST", "b.local")
+
+	var cfg struct {
+		Upstreams []Upstream
+	}
+	err := NewLoader(WithPrefix("IDXERR")).Load(&cfg)
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("Load() = %v, want *LoadError", err)
+	}
+	if len(loadErr.Fields) != 1 || loadErr.Fields[0].Field != "Upstreams[0].Port" || loadErr.Fields[0].Key != "IDXERR_UPSTREAMS_0_PORT" {
+		t.Errorf("Load() fields = %v, want Upstreams[0].Port failure", loadErr.Fields)
+	}
+
+	wantMissing := []MissingField{
+		{Key: "IDXERR_UPSTREAMS_0_HOST", Field: "Upstreams[0].Host"},
+	}
+	if loadErr.Missing == nil || !reflect.DeepEqual(loadErr.Missing.Fields, wantMissing) {
+		t.Errorf("Load() missing = %v, want %v", loadErr.Missing, wantMissing)
+	}
+	if len(cfg.Upstreams) != 2 || cfg.Upstreams[1].Host != "b.local" {
+		t.Errorf("Load() upstreams = %+v, want 2 elements", cfg.Upstreams)
+	}
+}
+
+func TestLoadStructSliceSparse(t *testing.T) {
+	type Upstream struct {
+		Host string
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{name: "gap", env: map[string]string{"SPARSE_UPSTREAMS_0_HOST": "a", "SPARSE_UPSTREAMS_2_HOST": "c"}},
+		{name: "not from zero", env: map[string]string{"SPARSE_UPSTREAMS_3_HOST": "d"}},
+		{name: "huge index", env: map[string]string{"SPARSE_UPSTREAMS_100000000_HOST": "x"}},
+		{name: "non-canonical index", env: map[string]string{"SPARSE_UPSTREAMS_+1_HOST": "b", "SPARSE_UPSTREAMS_02_HOST": "c", "SPARSE_UPSTREAMS_2_HOST": "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var cfg struct {
+				Upstreams []Upstream
+			}
+			err := NewLoader(WithPrefix("SPARSE")).Load(&cfg)
+			if !errors.Is(err, ErrSparseIndex) {
+				t.Fatalf("Load() = %v, want ErrSparseIndex", err)
+			}
+
+			var loadErr *LoadError
+			if !errors.As(err, &loadErr) || loadErr.Fields[0].Key != "SPARSE_UPSTREAMS_*" {
+				t.Errorf("Load() = %v, want failure on SPARSE_UPSTREAMS_*", err)
+			}
+			if cfg.Upstreams != nil {
+				t.Errorf("Load() upstreams = %+v, want nil", cfg.Upstreams)
+			}
+		})
+	}
+}
+
+func TestCountIndexedKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		suffixes map[string]string
+		want     int
+		wantErr  bool
+	}{
+		{name: "none", suffixes: map[string]string{"HOST": "a"}, want: 0},
+		{name: "contiguous", suffixes: map[string]string{"0_HOST": "a", "0_PORT": "1", "1_HOST": "b"}, want: 2},
+		{name: "non-canonical ignored", suffixes: map[string]string{"0_HOST": "a", "+1_HOST": "b", "01_HOST": "c"}, want: 1},
+		{name: "gap", suffixes: map[string]string{"0_HOST": "a", "2_HOST": "c"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := countIndexedKeys(tt.suffixes)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("countIndexedKeys() = %d, %v, want %d (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadTagOptions(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(secretPath, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	t.Setenv("OPT_DB_URL", "postgres://${OPT_DB_HOST}:5432")
+	t.Setenv("OPT_DB_HOST", "db.local")
+	t.Setenv("OPT_PASSWORD_PATH", secretPath)
+	t.Setenv("OPT_TOKEN", "abc")
+	t.Setenv("OPT_HOSTS", "a;b;c")
+
+	type Config struct {
+		DatabaseURL string   `env:"DB_URL,required,expand"`
+		Password    string   `env:"PASSWORD_PATH,file"`
+		Token       string   `env:"TOKEN,unset"`
+		Hosts       []string `env:"HOSTS,sep=;"`
+		Port        int      `env:"PORT,default=8080"`
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("OPT")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		DatabaseURL: "postgres://db.local:5432",
+		Password:    "s3cr3t",
+		Token:       "abc",
+		Hosts:       []string{"a", "b", "c"},
+		Port:        8080,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+	if _, ok := os.LookupEnv("OPT_TOKEN"); ok {
+		t.Errorf("Load() = OPT_TOKEN still set, want it unset")
+	}
+}
+
+func TestLoadTagOptionErrors(t *testing.T) {
+	t.Setenv("OPTERR_NAME", "")
+	t.Setenv("OPTERR_PIN", "12ab")
+
+	var cfg struct {
+		Name string `env:"NAME,notEmpty"`
+		Pin  int    `env:"PIN,secret"`
+		Key  string `env:"KEY,required"`
+	}
+	err := NewLoader(WithPrefix("OPTERR")).Load(&cfg)
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || len(loadErr.Fields) != 2 {
+		t.Fatalf("Load() = %v, want two field failures", err)
+	}
+	if !errors.Is(err, ErrEmptyValue) {
+		t.Errorf("errors.Is(err, ErrEmptyValue) = false, want true")
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("errors.Is(err, strconv.ErrSyntax) = false, want true")
+	}
+	if strings.Contains(err.Error(), "12ab") {
+		t.Errorf("Error() = %q, leaks the secret value", err.Error())
+	}
+	if !IsMissingError(err) {
+		t.Errorf("IsMissingError(err) = false, want true")
+	}
+}
+
+func TestLoadNestedPrefixes(t *testing.T) {
+	type Base struct {
+		LogLevel string
+	}
+	type Database struct {
+		Host string
+		Port int
+	}
+	type Config struct {
+		Base     `env:",inline"`
+		Database Database `envPrefix:"DB_"`
+		Cache    Database `envPrefix:"REDIS"`
+	}
+
+	t.Setenv("NEST_LOG_LEVEL", "debug")
+	t.Setenv("NEST_DB_HOST", "db.local")
+	t.Setenv("NEST_DB_PORT", "5432")
+	t.Setenv("NEST_REDIS_HOST", "redis.local")
+
+	var got Config
+	if err := NewLoader(WithPrefix("NEST")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		Base:     Base{LogLevel: "debug"},
+		Database: Database{Host: "db.local", Port: 5432},
+		Cache:    Database{Host: "redis.local"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadNilStructPointers(t *testing.T) {
+	type Cert struct {
+		Path string
+	}
+	type TLS struct {
+		Enabled bool   `default:"true"`
+		Key     string `required:"true"`
+		Cert    *Cert
+	}
+	type Config struct {
+		TLS     *TLS
+		Metrics *struct {
+			Port int `default:"9090"`
+		}
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(t *testing.T, cfg Config)
+	}{
+		{
+			name: "sections stay nil without variables",
+			env:  nil,
+			want: func(t *testing.T, cfg Config) {
+				if cfg.TLS != nil || cfg.Metrics != nil {
+					t.Errorf("Load() = TLS %v, Metrics %v, want nil", cfg.TLS, cfg.Metrics)
+				}
+			},
+		},
+		{
+			name: "section allocated when one variable is set",
+			env:  map[string]string{"LAZY_TLS_KEY": "key.pem"},
+			want: func(t *testing.T, cfg Config) {
+				if cfg.TLS == nil || cfg.TLS.Key != "key.pem" || !cfg.TLS.Enabled || cfg.TLS.Cert != nil {
+					t.Errorf("Load() = TLS %+v, want key.pem with defaults and nil Cert", cfg.TLS)
+				}
+			},
+		},
+		{
+			name: "deep section allocates every parent",
+			env:  map[string]string{"LAZY_TLS_KEY": "key.pem", "LAZY_TLS_CERT_PATH": "cert.pem"},
+			want: func(t *testing.T, cfg Config) {
+				if cfg.TLS == nil || cfg.TLS.Cert == nil || cfg.TLS.Cert.Path != "cert.pem" {
+					t.Errorf("Load() = TLS %+v, want Cert.Path cert.pem", cfg.TLS)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var cfg Config
+			if err := NewLoader(WithPrefix("LAZY")).Load(&cfg); err != nil {
+				t.Fatalf("Load() = unexpected error: %v", err)
+			}
+			tt.want(t, cfg)
+		})
+	}
+}
+
+func TestLoadNilStructPointerRequired(t *testing.T) {
+	t.Setenv("LAZYREQ_TLS_ENABLED", "true")
+
+	var cfg struct {
+		TLS *struct {
+			Enabled bool
+			Key     string `required:"true"`
+		}
+	}
+	err := NewLoader(WithPrefix("LAZYREQ")).Load(&cfg)
+	if !IsMissingError(err) || cfg.TLS == nil {
+		t.Errorf("Load() = %v, TLS %v, want missing LAZYREQ_TLS_KEY", err, cfg.TLS)
+	}
+}
+
+func TestLoadOverflow(t *testing.T) {
+	t.Setenv("OVF_RETRIES", "300")
+	t.Setenv("OVF_MASK", "0xFF")
+
+	var cfg struct {
+		Retries int8
+		Mask    uint8 `env:"MASK,base=0"`
+	}
+	err := NewLoader(WithPrefix("OVF")).Load(&cfg)
+
+	if !IsOverflowError(err) || !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("Load() = %v, want *OverflowError", err)
+	}
+	if want := `Retries (OVF_RETRIES, int8): value "300" overflows int8`; !strings.Contains(err.Error(), want) {
+		t.Errorf("Error() = %q, want it to contain %q", err.Error(), want)
+	}
+	if cfg.Retries != 0 || cfg.Mask != 255 {
+		t.Errorf("Load() = %+v, want Retries untouched and Mask 255", cfg)
+	}
+}
+
+func TestLoadFileSuffix(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	t.Setenv("SECRETS_DB_PASSWORD_FILE", write("password", "s3cr3t\n"))
+	t.Setenv("SECRETS_DB_PORT_FILE", write("port", "5432\r\n"))
+	t.Setenv("SECRETS_API_KEY", "direct")
+	t.Setenv("SECRETS_API_KEY_FILE", write("api_key", "from-file"))
+	t.Setenv("SECRETS_TLS_KEY_FILE", write("tls_key", "tls"))
+	t.Setenv("SECRETS_DB_HOST", "")
+	t.Setenv("SECRETS_DB_HOST_FILE", write("host", "db.local"))
+	t.Setenv("SECRETS_DB_NAME", "")
+	t.Setenv("SECRETS_DB_NAME_FILE", write("name", "from-file"))
+
+	type Config struct {
+		DbPassword string `required:"true"`
+		DbPort     int
+		DbUser     string `default:"postgres"`
+		DbHost     string `default:"localhost"`
+		DbName     string `allowEmpty:"true"`
+		APIKey     string `env:"API_KEY"`
+		TLS        *struct {
+			Key string
+		}
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("SECRETS"), WithFileSuffix("_FILE")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	if got.DbPassword != "s3cr3t" || got.DbPort != 5432 || got.DbUser != "postgres" || got.APIKey != "direct" ||
+		got.DbHost != "db.local" || got.DbName != "" {
+		t.Errorf("Load() = %+v", got)
+	}
+	if got.TLS == nil || got.TLS.Key != "tls" {
+		t.Errorf("Load() TLS = %+v, want key from file", got.TLS)
+	}
+}
+
+func TestLoadFileSuffixErrors(t *testing.T) {
+	t.Setenv("SECRETSERR_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	type Config struct {
+		Password string
+		Token    string `required:"true"`
+	}
+
+	var cfg Config
+	err := NewLoader(WithPrefix("SECRETSERR"), WithFileSuffix("_FILE")).Load(&cfg)
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || len(loadErr.Fields) != 1 || loadErr.Fields[0].Key != "SECRETSERR_PASSWORD_FILE" {
+		t.Fatalf("Load() = %v, want SECRETSERR_PASSWORD_FILE failure", err)
+	}
+	if !errors.Is(err, os.ErrNotExist) || !IsMissingError(err) {
+		t.Errorf("Load() = %v, want os.ErrNotExist and missing SECRETSERR_TOKEN", err)
+	}
+
+	cfg = Config{}
+	if err := NewLoader(WithPrefix("SECRETSERR")).Load(&cfg); !IsMissingError(err) || IsLoadError(err) {
+		t.Errorf("Load() without suffix = %v, want only missing SECRETSERR_TOKEN", err)
+	}
+}
+
+func TestLoadFileSuffixSiblingField(t *testing.T) {
+	t.Setenv("SIBLINGFILE_LOG_FILE", filepath.Join(t.TempDir(), "app.log"))
+	t.Setenv("SIBLINGFILE_CERT_FILE", "cert.pem")
+
+	type Config struct {
+		Log      string `default:"info"`
+		LogFile  string
+		Cert     string
+		CertFile string
+	}
+
+	for _, options := range [][]Option{nil, {WithFileSuffix("_FILE")}} {
+		var got Config
+		if err := NewLoader(append(options, WithPrefix("SIBLINGFILE"))...).Load(&got); err != nil {
+			t.Fatalf("Load() = unexpected error: %v", err)
+		}
+		if got.Log != "info" || got.Cert != "" || got.CertFile != "cert.pem" || got.LogFile == "" {
+			t.Errorf("Load() = %+v, want sibling *_FILE fields left alone", got)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
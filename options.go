@@ -1,12 +1,21 @@
 package autoenv
 
+import "reflect"
+
 var defaultOptions = options{
 	prefix:     "",
 	logger:     &defaultLogger{},
 	filesPaths: []string{".env", ".env.local"},
+	dirs:       nil,
 	ignores:    []string{},
+	sources:    nil,
+	precedence: EnvOverFile,
+	parsers:    nil,
+	fileSuffix: "",
 	onlyEnvTag: false,
+	allowEmpty: false,
 	withFiles:  false,
+	export:     false,
 	verbose:    false,
 }
 
@@ -15,10 +24,19 @@ type options struct {
 	logger Logger
 
 	filesPaths []string
+	dirs       []string
 	ignores    []string
 
+	sources    []Source
+	precedence Precedence
+
+	parsers    map[reflect.Type]parserFunc
+	fileSuffix string
+
 	onlyEnvTag bool
+	allowEmpty bool
 	withFiles  bool
+	export     bool
 	verbose    bool
 }
 
@@ -48,6 +66,12 @@ func WithPath(fileName string) Option {
 	}
 }
 
+func WithDir(dir string) Option {
+	return func(o *options) {
+		o.dirs = append(o.dirs, dir)
+	}
+}
+
 func WithPrefix(prefix string) Option {
 	return func(o *options) {
 		o.prefix = prefix
@@ -83,3 +107,48 @@ func WithFiles() Option {
 		o.withFiles = true
 	}
 }
+
+func WithExport() Option {
+	return func(o *options) {
+		o.export = true
+	}
+}
+
+func WithPrecedence(precedence Precedence) Option {
+	return func(o *options) {
+		o.precedence = precedence
+	}
+}
+
+func WithSources(sources ...Source) Option {
+	return func(o *options) {
+		o.sources = sources
+	}
+}
+
+func WithAllowEmpty() Option {
+	return func(o *options) {
+		o.allowEmpty = true
+	}
+}
+
+func WithFileSuffix(suffix string) Option {
+	return func(o *options) {
+		o.fileSuffix = suffix
+	}
+}
+
+func WithParser[T any](parse func(string) (T, error)) Option {
+	return func(o *options) {
+		if o.parsers == nil {
+			o.parsers = make(map[reflect.Type]parserFunc)
+		}
+		o.parsers[reflect.TypeFor[T]()] = func(val string) (reflect.Value, error) {
+			v, err := parse(val)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(&v).Elem(), nil
+		}
+	}
+}
@@ -60,6 +60,7 @@ func TestOptions_Apply(t *testing.T) {
 				WithOnlyEnvTag(),
 				WithPrefix("VAR"),
 				WithFiles(),
+				WithFileSuffix("_PATH"),
 			},
 			want: options{
 				prefix:     "VAR",
@@ -69,6 +70,7 @@ func TestOptions_Apply(t *testing.T) {
 				onlyEnvTag: true,
 				verbose:    true,
 				withFiles:  true,
+				fileSuffix: "_PATH",
 			},
 		},
 	}
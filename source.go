@@ -0,0 +1,168 @@
+package autoenv
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Source identifies where the loader reads values from.
+type Source int
+
+const (
+	// SourceEnv is the process environment.
+	SourceEnv Source = iota
+	// SourceFiles are the env files configured with WithPaths, WithPath or WithFiles.
+	SourceFiles
+	// SourceDirs are the directories configured with WithDir, where each file
+	// holds the value of the variable it is named after.
+	SourceDirs
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceEnv:
+		return "env"
+	case SourceFiles:
+		return "files"
+	case SourceDirs:
+		return "dirs"
+	default:
+		return fmt.Sprintf("Source(%d)", int(s))
+	}
+}
+
+// Precedence decides which source wins when a key is defined in more than one.
+type Precedence int
+
+const (
+	// EnvOverFile lets exported variables override directories and env files,
+	// which then act as defaults.
+	EnvOverFile Precedence = iota
+	// FileOverEnv lets env files and directories override exported variables.
+	FileOverEnv
+)
+
+// environment resolves keys against the loader sources in order, the first
+// source defining a key wins. File values never leak into os.Environ unless
+// the loader was built WithExport.
+type environment struct {
+	sources []Source
+	files   map[string]string
+	dirs    map[string]string
+}
+
+func (l *Loader) newEnvironment() environment {
+	return environment{
+		sources: l.sources(),
+		files:   l.loadEnvFiles(),
+		dirs:    l.loadDirs(),
+	}
+}
+
+func (l *Loader) sources() []Source {
+	if len(l.options.sources) > 0 {
+		return l.options.sources
+	}
+
+	if l.options.precedence == FileOverEnv {
+		return []Source{SourceFiles, SourceDirs, SourceEnv}
+	}
+	return []Source{SourceEnv, SourceDirs, SourceFiles}
+}
+
+func (l *Loader) filesOverEnv() bool {
+	sources := l.sources()
+	files := slices.Index(sources, SourceFiles)
+	env := slices.Index(sources, SourceEnv)
+	return files >= 0 && (env < 0 || files < env)
+}
+
+// lookup returns the first non-empty value of key across the sources, so an
+// explicitly empty variable does not hide a value from a later source. Empty
+// is only returned, as found, when no source has a value.
+func (e environment) lookup(key string) (string, bool) {
+	found := false
+	for _, source := range e.sources {
+		if v, ok := e.lookupSource(source, key); ok {
+			if v != "" {
+				return v, true
+			}
+			found = true
+		}
+	}
+	return "", found
+}
+
+// lookupFirst returns the value of key from the first source defining it,
+// even when empty, for fields where an empty value is meaningful.
+func (e environment) lookupFirst(key string) (string, bool) {
+	for _, source := range e.sources {
+		if v, ok := e.lookupSource(source, key); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func (e environment) lookupSource(source Source, key string) (string, bool) {
+	switch source {
+	case SourceEnv:
+		return os.LookupEnv(key)
+	case SourceFiles:
+		v, ok := e.files[key]
+		return v, ok
+	case SourceDirs:
+		v, ok := e.dirs[key]
+		return v, ok
+	default:
+		return "", false
+	}
+}
+
+// expand replaces ${VAR} and $VAR references in val with their resolved values.
+func (e environment) expand(val string) string {
+	return os.Expand(val, func(key string) string {
+		v, _ := e.lookup(key)
+		return v
+	})
+}
+
+// scan returns every key starting with prefix, with the prefix removed,
+// resolved with the same precedence as lookup.
+func (e environment) scan(prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, source := range slices.Backward(e.sources) {
+		for key, val := range e.sourceValues(source) {
+			name, ok := strings.CutPrefix(key, prefix)
+			if !ok || name == "" {
+				continue
+			}
+			if _, found := values[name]; found && val == "" {
+				continue
+			}
+			values[name] = val
+		}
+	}
+	return values
+}
+
+func (e environment) sourceValues(source Source) map[string]string {
+	switch source {
+	case SourceEnv:
+		values := make(map[string]string)
+		for _, kv := range os.Environ() {
+			if key, val, ok := strings.Cut(kv, "="); ok {
+				values[key] = val
+			}
+		}
+		return values
+	case SourceFiles:
+		return e.files
+	case SourceDirs:
+		return e.dirs
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+package autoenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvironmentPrecedence(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{
+			name:    "DefaultEnvOverFile",
+			options: nil,
+			want:    "from-env",
+		},
+		{
+			name:    "EnvOverFile",
+			options: []Option{WithPrecedence(EnvOverFile)},
+			want:    "from-env",
+		},
+		{
+			name:    "FileOverEnv",
+			options: []Option{WithPrecedence(FileOverEnv)},
+			want:    "from-file",
+		},
+		{
+			name:    "SourcesFilesFirst",
+			options: []Option{WithSources(SourceFiles, SourceEnv)},
+			want:    "from-file",
+		},
+		{
+			name:    "SourcesOverridePrecedence",
+			options: []Option{WithPrecedence(FileOverEnv), WithSources(SourceEnv, SourceFiles)},
+			want:    "from-env",
+		},
+		{
+			name:    "SourcesEnvOnly",
+			options: []Option{WithPrecedence(FileOverEnv), WithSources(SourceEnv)},
+			want:    "from-env",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if err := os.WriteFile(path, []byte("PRECEDENCE_VALUE=from-file\n"), 0o600); err != nil {
+				t.Fatalf("failed to write env file: %v", err)
+			}
+			t.Setenv("PRECEDENCE_VALUE", "from-env")
+
+			loader := NewLoader(append(tt.options, WithPaths([]string{path}))...)
+			env := loader.newEnvironment()
+
+			if got, _ := env.lookup("PRECEDENCE_VALUE"); got != tt.want {
+				t.Errorf("lookup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentLookupMissing(t *testing.T) {
+	env := environment{
+		sources: []Source{SourceEnv, SourceFiles},
+		files:   map[string]string{"ONLY_IN_FILE": "file"},
+	}
+
+	if got, ok := env.lookup("ONLY_IN_FILE"); !ok || got != "file" {
+		t.Errorf("lookup() = %q, %v, want %q, true", got, ok, "file")
+	}
+	if _, ok := env.lookup("AUTOENV_SURELY_MISSING_KEY"); ok {
+		t.Errorf("lookup() = found missing key")
+	}
+}
+
+func TestEnvironmentLookupEmpty(t *testing.T) {
+	t.Setenv("LOOKUP_EMPTY_SHADOWED", "")
+	t.Setenv("LOOKUP_EMPTY_ONLY", "")
+
+	env := environment{
+		sources: []Source{SourceEnv, SourceFiles},
+		files:   map[string]string{"LOOKUP_EMPTY_SHADOWED": "file"},
+	}
+
+	tests := []struct {
+		key       string
+		want      string
+		wantFirst string
+	}{
+		{key: "LOOKUP_EMPTY_SHADOWED", want: "file", wantFirst: ""},
+		{key: "LOOKUP_EMPTY_ONLY", want: "", wantFirst: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got, ok := env.lookup(tt.key); !ok || got != tt.want {
+				t.Errorf("lookup() = %q, %v, want %q, true", got, ok, tt.want)
+			}
+			if got, ok := env.lookupFirst(tt.key); !ok || got != tt.wantFirst {
+				t.Errorf("lookupFirst() = %q, %v, want %q, true", got, ok, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestEnvironmentEmptyDoesNotShadowFiles(t *testing.T) {
+	type Config struct {
+		Shadow  int `default:"1"`
+		Cleared int `default:"1" allowEmpty:"true"`
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("SHADOW=8080\nCLEARED=8080\n"), 0o600); err != nil {
+		t.Fatalf("failed to write env file: %v", err)
+	}
+	t.Setenv("SHADOW", "")
+	t.Setenv("CLEARED", "")
+
+	var cfg Config
+	if err := NewLoader(WithPaths([]string{path})).Load(&cfg); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Config{Shadow: 8080, Cleared: 0}
+	if cfg != want {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
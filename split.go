@@ -0,0 +1,142 @@
+package autoenv
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	quote     = '"'
+	spaceTabs = " \t"
+)
+
+// defaultSeparators are the list and map separators used when a field does
+// not set its own, from the innermost level outward: "a:1,b:2;c:3|d:4".
+var defaultSeparators = []string{",", ";", "|"}
+
+// separator returns the separator splitting a value of list or map type t at
+// the current level of spec. Levels set with the sep option or tag are used
+// from the outermost inward, the others fall back to defaultSeparators by
+// their distance to the innermost level, so a plain []int still uses ",".
+func (l *Loader) separator(t reflect.Type, spec fieldSpec) string {
+	if spec.level < len(spec.seps) {
+		return spec.seps[spec.level]
+	}
+
+	depth := l.listDepth(t, spec)
+	return defaultSeparators[min(max(depth, 1), len(defaultSeparators))-1]
+}
+
+// listDepth counts the nested list and map levels of t that are split by a
+// separator, stopping at types decoded as a whole.
+func (l *Loader) listDepth(t reflect.Type, spec fieldSpec) int {
+	depth := 0
+	for {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+			continue
+		}
+		if l.isWholeValue(t, spec) {
+			return depth
+		}
+
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			depth++
+			t = t.Elem()
+		default:
+			return depth
+		}
+	}
+}
+
+// isWholeValue reports whether a list or map type is decoded from the value
+// as a whole rather than split into elements.
+func (l *Loader) isWholeValue(t reflect.Type, spec fieldSpec) bool {
+	if _, ok := l.options.parsers[t]; ok {
+		return true
+	}
+	if isBuiltinType(t) || t == ipType || implementsDecoder(reflect.PointerTo(t)) {
+		return true
+	}
+	return isBytesType(t) && (t.Kind() == reflect.Slice || spec.encoding != "")
+}
+
+// splitJSONArray decodes val when it is a JSON array. String elements are
+// unquoted and any other element is kept as its JSON text.
+func splitJSONArray(val string) ([]string, bool) {
+	val = strings.TrimSpace(val)
+	if len(val) < 2 || val[0] != '[' || val[len(val)-1] != ']' {
+		return nil, false
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(val), &raw); err != nil {
+		return nil, false
+	}
+
+	parts := make([]string, len(raw))
+	for i, r := range raw {
+		switch {
+		case len(r) > 0 && r[0] == quote:
+			if err := json.Unmarshal(r, &parts[i]); err != nil {
+				return nil, false
+			}
+		case string(r) == "null":
+			parts[i] = ""
+		default:
+			parts[i] = string(r)
+		}
+	}
+	return parts, true
+}
+
+// splitQuoted splits val on sep and trims each element. An element starting
+// with a double quote runs until the closing quote, so it can contain sep,
+// and "" inside it stands for a literal quote. Unterminated quotes are kept
+// as is.
+func splitQuoted(val, sep string) []string {
+	var parts []string
+	for {
+		if rest := strings.TrimLeft(val, spaceTabs); len(rest) > 0 && rest[0] == quote {
+			if elem, after, ok := readQuoted(rest); ok {
+				after = strings.TrimLeft(after, spaceTabs)
+				if after == "" {
+					return append(parts, elem)
+				}
+				if next, found := strings.CutPrefix(after, sep); found {
+					parts = append(parts, elem)
+					val = next
+					continue
+				}
+			}
+		}
+
+		elem, after, found := strings.Cut(val, sep)
+		parts = append(parts, strings.TrimSpace(elem))
+		if !found {
+			return parts
+		}
+		val = after
+	}
+}
+
+// readQuoted reads the quoted element at the start of s, returning it
+// unescaped along with the text after its closing quote.
+func readQuoted(s string) (string, string, bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != quote {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		return b.String(), s[i+1:], true
+	}
+	return "", "", false
+}
@@ -0,0 +1,156 @@
+package autoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitQuoted(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		sep   string
+		want  []string
+	}{
+		{name: "plain", value: "a, b ,c", sep: ",", want: []string{"a", "b", "c"}},
+		{name: "empty", value: "", sep: ",", want: []string{""}},
+		{name: "quoted separator", value: `"a,b", c`, sep: ",", want: []string{"a,b", "c"}},
+		{name: "quoted spaces kept", value: `" a ",b`, sep: ",", want: []string{" a ", "b"}},
+		{name: "escaped quote", value: `"say ""hi""",x`, sep: ",", want: []string{`say "hi"`, "x"}},
+		{name: "last element quoted", value: `x, "y,z"`, sep: ",", want: []string{"x", "y,z"}},
+		{name: "quote inside element", value: `a"b,c`, sep: ",", want: []string{`a"b`, "c"}},
+		{name: "unterminated quote", value: `"a,b`, sep: ",", want: []string{`"a`, "b"}},
+		{name: "text after closing quote", value: `"a"b,c`, sep: ",", want: []string{`"a"b`, "c"}},
+		{name: "custom separator", value: `a;"b;c";d`, sep: ";", want: []string{"a", "b;c", "d"}},
+		{name: "multi-char separator", value: `a||"b||c"||d`, sep: "||", want: []string{"a", "b||c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitQuoted(tt.value, tt.sep); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitQuoted(%q, %q) = %q, want %q", tt.value, tt.sep, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitJSONArray(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   []string
+		wantOK bool
+	}{
+		{name: "strings", value: `["a,b","c"]`, want: []string{"a,b", "c"}, wantOK: true},
+		{name: "numbers and bools", value: ` [1, 2.5, true] `, want: []string{"1", "2.5", "true"}, wantOK: true},
+		{name: "null element", value: `["a", null]`, want: []string{"a", ""}, wantOK: true},
+		{name: "objects kept as json", value: `[{"a":1}]`, want: []string{`{"a":1}`}, wantOK: true},
+		{name: "empty array", value: `[]`, want: []string{}, wantOK: true},
+		{name: "not json", value: `[a,b]`, wantOK: false},
+		{name: "not an array", value: `"a"`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := splitJSONArray(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("splitJSONArray(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitJSONArray(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSliceSeparators(t *testing.T) {
+	t.Setenv("SEP_PATTERNS", `"^a,b$", "^c$"`)
+	t.Setenv("SEP_DSNS", `["postgres://h/db?a=1,2","mysql://h/db"]`)
+	t.Setenv("SEP_PORTS", "[8080, 8081]")
+	t.Setenv("SEP_HOSTS", "a|b|c")
+
+	type Config struct {
+		Patterns []string
+		DSNs     []string `env:"DSNS"`
+		Ports    []int
+		Hosts    []string `env:"HOSTS,sep=|"`
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("SEP")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		Patterns: []string{"^a,b$", "^c$"},
+		DSNs:     []string{"postgres://h/db?a=1,2", "mysql://h/db"},
+		Ports:    []int{8080, 8081},
+		Hosts:    []string{"a", "b", "c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSeparator(t *testing.T) {
+	loader := NewLoader()
+
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		spec fieldSpec
+		want string
+	}{
+		{name: "flat slice", typ: reflect.TypeOf([]int{}), want: ","},
+		{name: "flat map", typ: reflect.TypeOf(map[string]int{}), want: ","},
+		{name: "nested slice outer", typ: reflect.TypeOf([][]int{}), want: ";"},
+		{name: "nested slice inner", typ: reflect.TypeOf([]int{}), spec: fieldSpec{level: 1}, want: ","},
+		{name: "slice of maps", typ: reflect.TypeOf([]map[string]string{}), want: ";"},
+		{name: "three levels", typ: reflect.TypeOf([][][]int{}), want: "|"},
+		{name: "bytes are whole values", typ: reflect.TypeOf([][]byte{}), want: ","},
+		{name: "decoders are whole values", typ: reflect.TypeOf([]testLabels{}), want: ","},
+		{name: "custom outer level", typ: reflect.TypeOf([][]int{}), spec: fieldSpec{seps: []string{"/"}}, want: "/"},
+		{name: "custom outer falls back inner", typ: reflect.TypeOf([]int{}), spec: fieldSpec{seps: []string{"/"}, level: 1}, want: ","},
+		{name: "custom inner level", typ: reflect.TypeOf([]int{}), spec: fieldSpec{seps: []string{"/", " "}, level: 1}, want: " "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loader.separator(tt.typ, tt.spec); got != tt.want {
+				t.Errorf("separator(%s) = %q, want %q", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadNestedSeparators(t *testing.T) {
+	t.Setenv("NESTSEP_SHARDS", "1,2,3;4,5;6")
+	t.Setenv("NESTSEP_ROUTES", "host:a,weight:10;host:b,weight:90")
+	t.Setenv("NESTSEP_GROUPS", "admins=alice/bob|ops=carol")
+	t.Setenv("NESTSEP_MATRIX", "[[1,2],[3,4]]")
+
+	type Config struct {
+		Shards [][]int
+		Routes []map[string]string
+		Groups map[string][]string `sep:"| /" env:",kvsep=="`
+		Matrix [2][2]int
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("NESTSEP")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		Shards: [][]int{{1, 2, 3}, {4, 5}, {6}},
+		Routes: []map[string]string{
+			{"host": "a", "weight": "10"},
+			{"host": "b", "weight": "90"},
+		},
+		Groups: map[string][]string{"admins": {"alice", "bob"}, "ops": {"carol"}},
+		Matrix: [2][2]int{{1, 2}, {3, 4}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package autoenv
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	tagOptionSeparator = ","
+	tagValueSeparator  = "="
+	tagSkip            = "-"
+)
+
+// tagOptions are the comma-separated options following the name in a tag,
+// either flags such as "required" or key=value pairs such as "sep=;".
+type tagOptions []string
+
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, ok := strings.Cut(tag, tagOptionSeparator)
+	if !ok {
+		return strings.TrimSpace(name), nil
+	}
+
+	var options tagOptions
+	for _, opt := range strings.Split(opts, tagOptionSeparator) {
+		if opt = strings.TrimSpace(opt); opt != "" {
+			options = append(options, opt)
+		}
+	}
+	return strings.TrimSpace(name), options
+}
+
+func (o tagOptions) has(name string) bool {
+	for _, opt := range o {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (o tagOptions) value(name string) (string, bool) {
+	for _, opt := range o {
+		if k, v, ok := strings.Cut(opt, tagValueSeparator); ok && k == name {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// fieldTag is the result of resolving the env and json tags of a field: the
+// env tag name wins over the json tag name, which wins over the field name.
+// Options always come from the env tag.
+type fieldTag struct {
+	name    string
+	options tagOptions
+	skip    bool
+}
+
+func resolveFieldTag(f reflect.StructField) fieldTag {
+	var tag fieldTag
+
+	if v, ok := f.Tag.Lookup(envTag); ok {
+		if v == tagSkip {
+			return fieldTag{skip: true}
+		}
+		tag.name, tag.options = parseTag(v)
+	}
+
+	if v, ok := f.Tag.Lookup(jsonTag); ok && tag.name == "" {
+		if v == tagSkip {
+			return fieldTag{skip: true}
+		}
+		tag.name, _ = parseTag(v)
+	}
+
+	if tag.name == "" {
+		tag.name = f.Name
+	}
+	return tag
+}
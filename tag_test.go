@@ -0,0 +1,95 @@
+package autoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		wantName string
+		wantOpts tagOptions
+	}{
+		{
+			name:     "name only",
+			tag:      "PORT",
+			wantName: "PORT",
+			wantOpts: nil,
+		},
+		{
+			name:     "name and options",
+			tag:      "PORT,required,default=8080",
+			wantName: "PORT",
+			wantOpts: tagOptions{"required", "default=8080"},
+		},
+		{
+			name:     "options only",
+			tag:      ",omitempty",
+			wantName: "",
+			wantOpts: tagOptions{"omitempty"},
+		},
+		{
+			name:     "spaces and empty options",
+			tag:      " PORT , required,, ",
+			wantName: "PORT",
+			wantOpts: tagOptions{"required"},
+		},
+		{
+			name:     "empty",
+			tag:      "",
+			wantName: "",
+			wantOpts: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotOpts := parseTag(tt.tag)
+			if gotName != tt.wantName {
+				t.Errorf("parseTag() name = %q, want %q", gotName, tt.wantName)
+			}
+			if !reflect.DeepEqual(gotOpts, tt.wantOpts) {
+				t.Errorf("parseTag() options = %q, want %q", gotOpts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestTagOptions(t *testing.T) {
+	opts := tagOptions{"required", "sep=;", "default=a=b"}
+
+	if !opts.has("required") {
+		t.Errorf("has(required) = false, want true")
+	}
+	if opts.has("sep") {
+		t.Errorf("has(sep) = true, want false")
+	}
+	if v, ok := opts.value("sep"); !ok || v != ";" {
+		t.Errorf("value(sep) = %q, %v, want %q, true", v, ok, ";")
+	}
+	if v, ok := opts.value("default"); !ok || v != "a=b" {
+		t.Errorf("value(default) = %q, %v, want %q, true", v, ok, "a=b")
+	}
+	if _, ok := opts.value("required"); ok {
+		t.Errorf("value(required) = found, want not found")
+	}
+}
+
+func TestLoadSkipMarker(t *testing.T) {
+	t.Setenv("SKIP_PORT", "8080")
+	t.Setenv("SKIP_SECRET", "leaked")
+
+	var cfg struct {
+		Port   int    `json:"port,omitempty"`
+		Secret string `json:"-"`
+		Other  string `env:"-"`
+	}
+	if err := NewLoader(WithPrefix("SKIP")).Load(&cfg); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+	if cfg.Port != 8080 || cfg.Secret != "" || cfg.Other != "" {
+		t.Errorf("Load() = %+v, want only Port set", cfg)
+	}
+}
@@ -0,0 +1,181 @@
+package autoenv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is a number of bytes loaded from human-friendly sizes such as
+// "512KiB", "10MB" or "1.5GiB". Decimal units (kB, MB, GB, TB, PB) are powers
+// of 1000 and binary units (KiB, MiB, GiB, TiB, PiB) powers of 1024. Units
+// are case-insensitive and a plain number is a number of bytes.
+type ByteSize uint64
+
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"kb":  KB,
+	"mb":  MB,
+	"gb":  GB,
+	"tb":  TB,
+	"pb":  PB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+	"pib": PiB,
+}
+
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '_'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	multiplier, ok := byteSizeUnits[unit]
+	if number == "" || !ok {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	number = strings.ReplaceAll(number, "_", "")
+	if !strings.Contains(number, ".") {
+		n, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+		}
+		if n > math.MaxUint64/uint64(multiplier) {
+			return 0, fmt.Errorf("invalid byte size %q: %w", s, strconv.ErrRange)
+		}
+		return ByteSize(n) * multiplier, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+	}
+	size := f * float64(multiplier)
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, strconv.ErrRange)
+	}
+	return ByteSize(size), nil
+}
+
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+func (b ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{
+		{PiB, "PiB"}, {TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"},
+	}
+	for _, u := range units {
+		if b >= u.size && b%u.size == 0 {
+			return fmt.Sprintf("%d%s", b/u.size, u.name)
+		}
+	}
+	return fmt.Sprintf("%dB", uint64(b))
+}
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// parseDuration extends time.ParseDuration with the "d" (24h) and "w" (7d)
+// units, e.g. "7d", "2w" or "1d12h".
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err == nil || !strings.ContainsAny(s, "dw") {
+		return d, err
+	}
+
+	orig := s
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	var total float64
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		number := s[:i]
+		s = s[i:]
+
+		j := strings.IndexFunc(s, func(r rune) bool {
+			return (r >= '0' && r <= '9') || r == '.'
+		})
+		if j < 0 {
+			j = len(s)
+		}
+		unit := s[:j]
+		s = s[j:]
+
+		var part float64
+		switch unit {
+		case "d", "w":
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			part = f * float64(day)
+			if unit == "w" {
+				part = f * float64(week)
+			}
+		default:
+			d, err := time.ParseDuration(number + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", orig)
+			}
+			part = float64(d)
+		}
+		total += part
+	}
+
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid duration %q: %w", orig, strconv.ErrRange)
+	}
+	if neg {
+		total = -total
+	}
+	return time.Duration(total), nil
+}
@@ -0,0 +1,136 @@
+package autoenv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ByteSize
+		wantErr bool
+	}{
+		{name: "plain bytes", input: "512", want: 512},
+		{name: "bytes unit", input: "512B", want: 512},
+		{name: "kibibytes", input: "512KiB", want: 512 * KiB},
+		{name: "megabytes", input: "10MB", want: 10 * MB},
+		{name: "fractional gibibytes", input: "1.5GiB", want: 3 * GiB / 2},
+		{name: "lowercase unit", input: "64mib", want: 64 * MiB},
+		{name: "space before unit", input: "2 TB", want: 2 * TB},
+		{name: "underscores", input: "1_000kB", want: 1000 * KB},
+		{name: "leading zero", input: "010MB", want: 10 * MB},
+		{name: "leading zero with 8", input: "08KB", want: 8 * KB},
+		{name: "fractional underscores", input: "1_024.5B", want: 1024},
+		{name: "unknown unit", input: "10XB", wantErr: true},
+		{name: "missing number", input: "MB", wantErr: true},
+		{name: "negative", input: "-1MB", wantErr: true},
+		{name: "overflow", input: "100000PiB", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseByteSize(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseByteSize(%q) = unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteSizeString(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{size: 0, want: "0B"},
+		{size: 1000, want: "1000B"},
+		{size: 512 * KiB, want: "512KiB"},
+		{size: 3 * GiB / 2, want: "1536MiB"},
+		{size: 2 * PiB, want: "2PiB"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.size.String(); got != tt.want {
+			t.Errorf("ByteSize(%d).String() = %q, want %q", uint64(tt.size), got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "standard units", input: "1h30m", want: time.Hour + 30*time.Minute},
+		{name: "days", input: "7d", want: 7 * day},
+		{name: "weeks", input: "2w", want: 2 * week},
+		{name: "mixed", input: "1w2d3h", want: week + 2*day + 3*time.Hour},
+		{name: "fractional day", input: "1.5d", want: 36 * time.Hour},
+		{name: "negative", input: "-1d", want: -day},
+		{name: "invalid unit", input: "1x", wantErr: true},
+		{name: "invalid day", input: "d", wantErr: true},
+		{name: "overflow", input: "100000000w", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDuration(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseDuration(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDuration(%q) = unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadUnits(t *testing.T) {
+	t.Setenv("UNITS_CACHE_SIZE", "1.5GiB")
+	t.Setenv("UNITS_BUFFERS", "4KiB, 1MB")
+	t.Setenv("UNITS_RETENTION", "2w")
+	t.Setenv("UNITS_WINDOWS", "1d, 12h")
+
+	type Config struct {
+		CacheSize ByteSize
+		Buffers   []ByteSize
+		Retention time.Duration
+		Windows   []time.Duration
+	}
+
+	var got Config
+	if err := NewLoader(WithPrefix("UNITS")).Load(&got); err != nil {
+		t.Fatalf("Load() = unexpected error: %v", err)
+	}
+
+	want := Config{
+		CacheSize: 3 * GiB / 2,
+		Buffers:   []ByteSize{4 * KiB, MB},
+		Retention: 2 * week,
+		Windows:   []time.Duration{day, 12 * time.Hour},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}